@@ -0,0 +1,158 @@
+package fields
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/cloudquery/plugin-sdk/v4/types"
+	"github.com/koltyakov/gosip/api"
+)
+
+// Type describes how a SharePoint field is mapped to a destination column
+type Type struct {
+	// Arrow type of a column
+	Arrow arrow.DataType
+	// Optional converter of a REST API value to a column value
+	convert func(value any) any
+}
+
+// Convert converts a REST API value to a column value
+func (t *Type) Convert(value any) any {
+	if value == nil || t.convert == nil {
+		return value
+	}
+	return t.convert(value)
+}
+
+// URL field (Hyperlink or Picture) value type
+var urlType = arrow.StructOf(
+	arrow.Field{Name: "url", Type: arrow.BinaryTypes.String, Nullable: true},
+	arrow.Field{Name: "description", Type: arrow.BinaryTypes.String, Nullable: true},
+)
+
+// registry maps field types (`TypeAsString`) to column types
+var registry = map[string]*Type{
+	"Integer":                {Arrow: arrow.PrimitiveTypes.Int32},
+	"Counter":                {Arrow: arrow.PrimitiveTypes.Int32},
+	"Text":                   {Arrow: arrow.BinaryTypes.String},
+	"Note":                   {Arrow: arrow.BinaryTypes.String},
+	"Choice":                 {Arrow: arrow.BinaryTypes.String},
+	"OutcomeChoice":          {Arrow: arrow.BinaryTypes.String},
+	"Computed":               {Arrow: arrow.BinaryTypes.String},
+	"File":                   {Arrow: arrow.BinaryTypes.String},
+	"ContentTypeId":          {Arrow: arrow.BinaryTypes.String},
+	"Number":                 {Arrow: arrow.PrimitiveTypes.Float64},
+	"Currency":               {Arrow: arrow.PrimitiveTypes.Float64},
+	"DateTime":               {Arrow: arrow.FixedWidthTypes.Timestamp_us},
+	"Boolean":                {Arrow: arrow.FixedWidthTypes.Boolean},
+	"Attachments":            {Arrow: arrow.FixedWidthTypes.Boolean},
+	"AllDayEvent":            {Arrow: arrow.FixedWidthTypes.Boolean},
+	"Guid":                   {Arrow: types.UUID},
+	"Lookup":                 {Arrow: arrow.PrimitiveTypes.Int32},
+	"User":                   {Arrow: arrow.PrimitiveTypes.Int32},
+	"LookupMulti":            {Arrow: arrow.ListOf(arrow.PrimitiveTypes.Int32)},
+	"UserMulti":              {Arrow: arrow.ListOf(arrow.PrimitiveTypes.Int32)},
+	"MultiChoice":            {Arrow: arrow.ListOf(arrow.BinaryTypes.String)},
+	"URL":                    {Arrow: urlType, convert: convertURL},
+	"Location":               {Arrow: types.ExtensionTypes.JSON},
+	"Geolocation":            {Arrow: types.ExtensionTypes.JSON},
+	"Thumbnail":              {Arrow: types.ExtensionTypes.JSON},
+	"Image":                  {Arrow: types.ExtensionTypes.JSON},
+	"TaxonomyFieldType":      {Arrow: types.ExtensionTypes.JSON},
+	"TaxonomyFieldTypeMulti": {Arrow: types.ExtensionTypes.JSON},
+}
+
+// kinds maps `FieldTypeKind` to field types for fields with custom `TypeAsString`
+var kinds = map[int]string{
+	1:  "Integer",
+	2:  "Text",
+	3:  "Note",
+	4:  "DateTime",
+	5:  "Counter",
+	6:  "Choice",
+	7:  "Lookup",
+	8:  "Boolean",
+	9:  "Number",
+	10: "Currency",
+	11: "URL",
+	12: "Computed",
+	14: "Guid",
+	15: "MultiChoice",
+	17: "Calculated",
+	18: "File",
+	19: "Attachments",
+	20: "User",
+	25: "ContentTypeId",
+	29: "AllDayEvent",
+	31: "Geolocation",
+	32: "OutcomeChoice",
+	33: "Location",
+	34: "Thumbnail",
+}
+
+var resultTypeRe = regexp.MustCompile(`ResultType="(\w+)"`)
+
+// TypeOf returns column type for a field, `ok` is false for unknown field types
+func TypeOf(field *api.FieldInfo) (t *Type, ok bool) {
+	typeName := field.TypeAsString
+	if _, known := registry[typeName]; !known && typeName != "Calculated" {
+		if k, known := kinds[field.FieldTypeKind]; known {
+			typeName = k
+			if strings.HasSuffix(field.TypeAsString, "Multi") && (k == "Lookup" || k == "User") {
+				typeName += "Multi"
+			}
+		}
+	}
+
+	// Calculated fields values are typed by formula's result type
+	if typeName == "Calculated" {
+		resultType := "Text"
+		if m := resultTypeRe.FindStringSubmatch(field.SchemaXML); len(m) > 1 {
+			resultType = m[1]
+		}
+		t, ok := registry[resultType]
+		if !ok {
+			t = registry["Text"]
+		}
+		return &Type{Arrow: t.Arrow, convert: convertCalculated}, true
+	}
+
+	t, ok = registry[typeName]
+	if !ok {
+		return &Type{Arrow: arrow.BinaryTypes.String}, false
+	}
+	return t, true
+}
+
+// convertURL maps `{ Url, Description }` to URL struct column
+// Rendered list data (CAML) returns URL values as `url, description` strings
+func convertURL(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return map[string]any{
+			"url":         v["Url"],
+			"description": v["Description"],
+		}
+	case string:
+		u, d, _ := strings.Cut(v, ", ")
+		return map[string]any{
+			"url":         u,
+			"description": d,
+		}
+	}
+	return nil
+}
+
+// convertCalculated strips a value type prefix which is returned for some
+// calculated fields, e.g. `float;#1.50000000000000`
+func convertCalculated(value any) any {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+	if _, v, found := strings.Cut(s, ";#"); found {
+		return v
+	}
+	return s
+}
@@ -0,0 +1,82 @@
+package fields
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/koltyakov/gosip/api"
+)
+
+func TestConvertURL(t *testing.T) {
+	cases := []struct {
+		value any
+		want  any
+	}{
+		{
+			map[string]any{"Url": "https://contoso.com", "Description": "Contoso"},
+			map[string]any{"url": "https://contoso.com", "description": "Contoso"},
+		},
+		{
+			map[string]any{"Url": "https://contoso.com"},
+			map[string]any{"url": "https://contoso.com", "description": nil},
+		},
+		{
+			"https://contoso.com, Contoso, Inc.",
+			map[string]any{"url": "https://contoso.com", "description": "Contoso, Inc."},
+		},
+		{
+			"https://contoso.com",
+			map[string]any{"url": "https://contoso.com", "description": ""},
+		},
+		{42, nil},
+	}
+
+	for _, c := range cases {
+		if got := convertURL(c.value); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("convertURL(%v) = %v, want %v", c.value, got, c.want)
+		}
+	}
+}
+
+func TestConvertCalculated(t *testing.T) {
+	cases := []struct {
+		value any
+		want  any
+	}{
+		{"float;#1.50000000000000", "1.50000000000000"},
+		{"string;#Done", "Done"},
+		{"Done", "Done"},
+		{"", ""},
+		{1.5, 1.5},
+	}
+
+	for _, c := range cases {
+		if got := convertCalculated(c.value); got != c.want {
+			t.Errorf("convertCalculated(%v) = %v, want %v", c.value, got, c.want)
+		}
+	}
+}
+
+func TestTypeOf(t *testing.T) {
+	cases := []struct {
+		field *api.FieldInfo
+		want  arrow.DataType
+		ok    bool
+	}{
+		{&api.FieldInfo{TypeAsString: "Number"}, arrow.PrimitiveTypes.Float64, true},
+		{&api.FieldInfo{TypeAsString: "URL"}, urlType, true},
+		{&api.FieldInfo{TypeAsString: "CustomLookup", FieldTypeKind: 7}, arrow.PrimitiveTypes.Int32, true},
+		{&api.FieldInfo{TypeAsString: "CustomLookupMulti", FieldTypeKind: 7}, arrow.ListOf(arrow.PrimitiveTypes.Int32), true},
+		{&api.FieldInfo{TypeAsString: "Calculated", SchemaXML: `<Field Type="Calculated" ResultType="Number" />`}, arrow.PrimitiveTypes.Float64, true},
+		{&api.FieldInfo{TypeAsString: "Calculated"}, arrow.BinaryTypes.String, true},
+		{&api.FieldInfo{TypeAsString: "Unknown"}, arrow.BinaryTypes.String, false},
+	}
+
+	for _, c := range cases {
+		got, ok := TypeOf(c.field)
+		if ok != c.ok || !arrow.TypeEqual(got.Arrow, c.want) {
+			t.Errorf("TypeOf(%s) = %v, %v, want %v, %v", c.field.TypeAsString, got.Arrow, ok, c.want, c.ok)
+		}
+	}
+}
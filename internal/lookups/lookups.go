@@ -0,0 +1,463 @@
+package lookups
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+	"github.com/koltyakov/gosip/api"
+	"github.com/rs/zerolog"
+)
+
+// Max number of IDs requested in a single `$filter` to keep request URLs short
+const batchSize = 50
+
+// Field is a selected lookup or user field which is resolved to display values
+type Field struct {
+	Prop        string // Selected prop, e.g. `AuthorId`
+	Name        string // Companion columns name prefix, e.g. `author`
+	User        bool
+	Multi       bool
+	LookupList  string
+	LookupWebID string
+	LookupField string
+}
+
+// Companion columns suffixes and injected item props attributes
+var (
+	userAttrs   = []string{"email", "login", "title"}
+	lookupAttrs = []string{"value"}
+)
+
+type fieldInfo struct {
+	EntityPropertyName string `json:"EntityPropertyName"`
+	TypeAsString       string `json:"TypeAsString"`
+	LookupList         string `json:"LookupList"`
+	LookupWebID        string `json:"LookupWebId"`
+	LookupField        string `json:"LookupField"`
+}
+
+// GetFields maps selected props to lookup and user fields
+// fieldsData is a normalized JSON array of fields, fieldsMapping are selected props aliases
+func GetFields(props []string, fieldsData []byte, fieldsMapping map[string]string) ([]*Field, error) {
+	var fieldsInfo []*fieldInfo
+	if err := json.Unmarshal(fieldsData, &fieldsInfo); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal fields: %w", err)
+	}
+
+	var fields []*Field
+	for _, prop := range props {
+		var field *Field
+		for _, f := range fieldsInfo {
+			isUser := strings.HasPrefix(f.TypeAsString, "User")
+			isLookup := strings.HasPrefix(f.TypeAsString, "Lookup")
+			if (isUser || isLookup) && f.EntityPropertyName+"Id" == prop {
+				field = &Field{
+					User:        isUser,
+					Multi:       strings.HasSuffix(f.TypeAsString, "Multi"),
+					LookupList:  strings.Trim(f.LookupList, "{}"),
+					LookupWebID: strings.Trim(f.LookupWebID, "{}"),
+					LookupField: f.LookupField,
+				}
+				break
+			}
+		}
+
+		// Author and Editor are not always a part of content type fields
+		if field == nil && (prop == "AuthorId" || prop == "EditorId") {
+			field = &Field{User: true}
+		}
+
+		// Lookups to non-list sources (e.g. `FileRef` based) can't be resolved
+		if field == nil || (!field.User && (field.LookupList == "" || field.LookupList == "Self" || field.LookupField == "")) {
+			continue
+		}
+
+		field.Prop = prop
+		field.Name = util.NormalizeEntityName(strings.TrimSuffix(prop, "Id"))
+		if alias, ok := fieldsMapping[prop]; ok {
+			field.Name = strings.TrimSuffix(util.NormalizeEntityName(alias), "_id")
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// Columns returns companion columns of lookup and user fields
+// Columns which names are already taken by the table are skipped
+func Columns(fields []*Field, table *schema.Table) []schema.Column {
+	var cols []schema.Column
+	for _, field := range fields {
+		attrs := lookupAttrs
+		if field.User {
+			attrs = userAttrs
+		}
+
+		for _, attr := range attrs {
+			name := field.Name + "_" + attr
+			if table.Column(name) != nil {
+				continue
+			}
+
+			var colType arrow.DataType = arrow.BinaryTypes.String
+			if field.Multi {
+				colType = arrow.ListOf(arrow.BinaryTypes.String)
+			}
+
+			prop := itemProp(field, attr)
+			cols = append(cols, schema.Column{
+				Name:        name,
+				Description: field.Prop + " " + attr,
+				Type:        colType,
+				Resolver: func(ctx context.Context, meta schema.ClientMeta, resource *schema.Resource, c schema.Column) error {
+					return resource.Set(c.Name, resource.Item.(map[string]any)[prop])
+				},
+			})
+		}
+	}
+	return cols
+}
+
+// itemProp is a prop injected into list items with a resolved value
+func itemProp(field *Field, attr string) string {
+	return "@" + field.Prop + "." + attr
+}
+
+// Resolver resolves lookup and user IDs to display values
+// Resolved values are cached for the duration of a sync
+type Resolver struct {
+	sp     *api.SP
+	logger zerolog.Logger
+	fields []*Field
+
+	mu         sync.Mutex
+	cache      map[string]map[string]map[string]any // source key -> ID -> attributes
+	webURLs    map[string]string                    // web ID -> web URL
+	listFields map[string][]*Field                  // list ID -> fields with the list's lookup settings
+}
+
+func NewResolver(sp *api.SP, logger zerolog.Logger, fields []*Field) *Resolver {
+	return &Resolver{
+		sp:         sp,
+		logger:     logger,
+		fields:     fields,
+		cache:      map[string]map[string]map[string]any{},
+		webURLs:    map[string]string{},
+		listFields: map[string][]*Field{},
+	}
+}
+
+// Resolve injects lookup and user display values into list items
+// webURL and listID are the web and the list of items, empty for the list the fields are taken from
+// Lookup settings (lookup list and web IDs) are specific to a list, so they are resolved per list
+func (r *Resolver) Resolve(webURL string, listID string, items []map[string]any) error {
+	if r == nil || len(r.fields) == 0 {
+		return nil
+	}
+
+	fields, err := r.getListFields(webURL, listID)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		values, err := r.getValues(webURL, field, items)
+		if err != nil {
+			return err
+		}
+
+		attrs := lookupAttrs
+		if field.User {
+			attrs = userAttrs
+		}
+
+		for _, item := range items {
+			ids := getIDs(item[field.Prop])
+			for _, attr := range attrs {
+				var value any
+				if field.Multi {
+					vals := make([]any, 0, len(ids))
+					for _, id := range ids {
+						if v, ok := values[id]; ok && v[attr] != nil {
+							vals = append(vals, v[attr])
+						}
+					}
+					value = vals
+				} else if len(ids) > 0 {
+					if v, ok := values[ids[0]]; ok {
+						value = v[attr]
+					}
+				}
+				item[itemProp(field, attr)] = value
+			}
+		}
+	}
+
+	return nil
+}
+
+// getListFields returns resolved fields with lookup settings of a list
+// Lookup fields which are not found in the list are skipped
+func (r *Resolver) getListFields(webURL string, listID string) ([]*Field, error) {
+	if listID == "" {
+		return r.fields, nil
+	}
+
+	r.mu.Lock()
+	fields, ok := r.listFields[listID]
+	r.mu.Unlock()
+	if ok {
+		return fields, nil
+	}
+
+	resp, err := r.getWeb(webURL).Lists().GetByID(listID).Fields().Get()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lookup fields: %w", err)
+	}
+
+	var fieldsInfo []*fieldInfo
+	if err := json.Unmarshal(resp.Normalized(), &fieldsInfo); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal fields: %w", err)
+	}
+
+	fields = make([]*Field, 0, len(r.fields))
+	for _, field := range r.fields {
+		if field.User {
+			fields = append(fields, field)
+			continue
+		}
+
+		for _, f := range fieldsInfo {
+			if f.EntityPropertyName+"Id" != field.Prop || !strings.HasPrefix(f.TypeAsString, "Lookup") {
+				continue
+			}
+			listField := *field
+			listField.LookupList = strings.Trim(f.LookupList, "{}")
+			listField.LookupWebID = strings.Trim(f.LookupWebID, "{}")
+			listField.LookupField = f.LookupField
+			if listField.LookupList != "" && listField.LookupList != "Self" && listField.LookupField != "" {
+				fields = append(fields, &listField)
+			}
+			break
+		}
+	}
+
+	r.mu.Lock()
+	r.listFields[listID] = fields
+	r.mu.Unlock()
+
+	return fields, nil
+}
+
+// getValues returns cached values of a field source, missing IDs are requested in batches
+// The lock is held only while accessing the cache, so lists and partitions are resolved concurrently
+func (r *Resolver) getValues(webURL string, field *Field, items []map[string]any) (map[string]map[string]any, error) {
+	web := r.getWeb(webURL)
+	key := webURL + "|users"
+	if !field.User {
+		if field.LookupWebID != "" {
+			lookupWebURL, err := r.getWebURL(webURL, field.LookupWebID)
+			if err != nil {
+				r.logger.Warn().Err(err).Str("field", field.Prop).Msg("failed to get lookup web, using list web")
+			} else {
+				webURL = lookupWebURL
+				web = r.getWeb(webURL)
+			}
+		}
+		key = webURL + "|" + field.LookupList + "|" + field.LookupField
+	}
+
+	r.mu.Lock()
+	cached, ok := r.cache[key]
+	if !ok {
+		cached = map[string]map[string]any{}
+		r.cache[key] = cached
+	}
+
+	values := map[string]map[string]any{}
+	var missing []string
+	for _, item := range items {
+		for _, id := range getIDs(item[field.Prop]) {
+			if _, ok := values[id]; ok {
+				continue
+			}
+			if v, ok := cached[id]; ok {
+				values[id] = v
+				continue
+			}
+			values[id] = nil
+			missing = append(missing, id)
+		}
+	}
+	r.mu.Unlock()
+
+	for start := 0; start < len(missing); start += batchSize {
+		end := start + batchSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+		batch := missing[start:end]
+
+		fetched := map[string]map[string]any{}
+		var err error
+		if field.User {
+			err = r.getUsers(web, batch, fetched)
+		} else {
+			err = r.getLookups(web, field, batch, fetched)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Not found IDs (e.g. deleted lookup items) are cached as empty to avoid requesting them again
+		for _, id := range batch {
+			if _, ok := fetched[id]; !ok {
+				fetched[id] = map[string]any{}
+			}
+		}
+
+		r.mu.Lock()
+		for id, v := range fetched {
+			cached[id] = v
+			values[id] = v
+		}
+		r.mu.Unlock()
+	}
+
+	return values, nil
+}
+
+func (r *Resolver) getUsers(web *api.Web, ids []string, values map[string]map[string]any) error {
+	resp, err := web.SiteUsers().
+		Select("Id,Email,LoginName,Title").
+		Filter(idsFilter(ids)).
+		Top(len(ids)).
+		Get()
+	if err != nil {
+		return fmt.Errorf("failed to get users: %w", err)
+	}
+
+	var users []struct {
+		ID        int    `json:"Id"`
+		Email     string `json:"Email"`
+		LoginName string `json:"LoginName"`
+		Title     string `json:"Title"`
+	}
+	if err := json.Unmarshal(resp.Normalized(), &users); err != nil {
+		return fmt.Errorf("failed to unmarshal users: %w", err)
+	}
+
+	for _, u := range users {
+		values[fmt.Sprintf("%d", u.ID)] = map[string]any{
+			"email": u.Email,
+			"login": u.LoginName,
+			"title": u.Title,
+		}
+	}
+
+	return nil
+}
+
+func (r *Resolver) getLookups(web *api.Web, field *Field, ids []string, values map[string]map[string]any) error {
+	resp, err := web.Lists().GetByID(field.LookupList).Items().
+		Select("Id," + field.LookupField).
+		Filter(idsFilter(ids)).
+		Top(len(ids)).
+		Get()
+	if err != nil {
+		return fmt.Errorf("failed to get lookup values: %w", err)
+	}
+
+	var lookups []map[string]any
+	if err := json.Unmarshal(resp.Normalized(), &lookups); err != nil {
+		return fmt.Errorf("failed to unmarshal lookup values: %w", err)
+	}
+
+	for _, lookup := range lookups {
+		var value any
+		if v := util.GetRespValByProp(lookup, field.LookupField); v != nil {
+			value = fmt.Sprintf("%v", v)
+		}
+		values[formatID(lookup["Id"])] = map[string]any{"value": value}
+	}
+
+	return nil
+}
+
+func (r *Resolver) getWeb(webURL string) *api.Web {
+	if webURL == "" {
+		return r.sp.Web()
+	}
+	return util.GetWeb(r.sp, webURL)
+}
+
+// getWebURL returns lookup web URL by its ID
+// The web is opened within the site collection of the list web, lookups can't target other site collections
+func (r *Resolver) getWebURL(webURL string, webID string) (string, error) {
+	r.mu.Lock()
+	lookupWebURL, ok := r.webURLs[webID]
+	r.mu.Unlock()
+	if ok {
+		return lookupWebURL, nil
+	}
+
+	site := r.sp.Site()
+	if webURL != "" {
+		site = site.FromURL(webURL + "/_api/Site")
+	}
+
+	resp, err := site.OpenWebByID(webID)
+	if err != nil {
+		return "", err
+	}
+
+	lookupWebURL = resp.Data().URL
+
+	r.mu.Lock()
+	r.webURLs[webID] = lookupWebURL
+	r.mu.Unlock()
+
+	return lookupWebURL, nil
+}
+
+// getIDs returns lookup IDs of a single or multi value lookup prop
+func getIDs(value any) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []any:
+		ids := make([]string, 0, len(v))
+		for _, id := range v {
+			if id != nil {
+				ids = append(ids, formatID(id))
+			}
+		}
+		return ids
+	default:
+		return []string{formatID(v)}
+	}
+}
+
+// formatID formats lookup ID avoiding exponent notation of large JSON numbers
+func formatID(id any) string {
+	if f, ok := id.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", id)
+}
+
+func idsFilter(ids []string) string {
+	filters := make([]string, len(ids))
+	for i, id := range ids {
+		filters[i] = "Id eq " + id
+	}
+	return strings.Join(filters, " or ")
+}
@@ -0,0 +1,43 @@
+package lookups
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetIDs(t *testing.T) {
+	cases := []struct {
+		value any
+		want  []string
+	}{
+		{nil, nil},
+		{float64(7), []string{"7"}},
+		{float64(12345678), []string{"12345678"}},
+		{"42", []string{"42"}},
+		{[]any{}, []string{}},
+		{[]any{float64(1), nil, float64(3)}, []string{"1", "3"}},
+	}
+
+	for _, c := range cases {
+		if got := getIDs(c.value); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("getIDs(%v) = %q, want %q", c.value, got, c.want)
+		}
+	}
+}
+
+func TestIDsFilter(t *testing.T) {
+	cases := []struct {
+		ids  []string
+		want string
+	}{
+		{nil, ""},
+		{[]string{"7"}, "Id eq 7"},
+		{[]string{"1", "2", "3"}, "Id eq 1 or Id eq 2 or Id eq 3"},
+	}
+
+	for _, c := range cases {
+		if got := idsFilter(c.ids); got != c.want {
+			t.Errorf("idsFilter(%q) = %q, want %q", c.ids, got, c.want)
+		}
+	}
+}
@@ -21,3 +21,22 @@ func IsNotFound(err error) bool {
 
 	return false
 }
+
+// IsThrottled unwraps API response errors checking for list view threshold errors
+// Requests rate limiting (429) is not treated as throttling, such requests are retried by the client
+func IsThrottled(err error) bool {
+	if err == nil {
+		return false
+	}
+	for {
+		msg := err.Error()
+		if strings.Contains(msg, "SPQueryThrottledException") || strings.Contains(msg, "list view threshold") {
+			return true
+		}
+		if err = errors.Unwrap(err); err == nil {
+			break
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,26 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIsThrottled(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("500 Internal Server Error :: Microsoft.SharePoint.SPQueryThrottledException"), true},
+		{fmt.Errorf("failed to get items: %w", errors.New("The attempted operation is prohibited because it exceeds the list view threshold.")), true},
+		{errors.New("429 Too Many Requests"), false},
+		{errors.New("404 Not Found"), false},
+	}
+
+	for _, c := range cases {
+		if got := IsThrottled(c.err); got != c.want {
+			t.Errorf("IsThrottled(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Joins OData filters with `and` operator skipping empty ones
+func AndFilters(filters ...string) string {
+	parts := []string{}
+	for _, filter := range filters {
+		if strings.TrimSpace(filter) == "" {
+			continue
+		}
+		parts = append(parts, "("+filter+")")
+	}
+	if len(parts) == 1 {
+		return strings.TrimSuffix(strings.TrimPrefix(parts[0], "("), ")")
+	}
+	return strings.Join(parts, " and ")
+}
+
+// Formats time as OData datetime literal
+func ODataDateTime(t time.Time) string {
+	return fmt.Sprintf("datetime'%s'", t.UTC().Format(time.RFC3339))
+}
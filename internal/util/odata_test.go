@@ -0,0 +1,34 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAndFilters(t *testing.T) {
+	cases := []struct {
+		filters []string
+		want    string
+	}{
+		{nil, ""},
+		{[]string{"", " "}, ""},
+		{[]string{"Id gt 10"}, "Id gt 10"},
+		{[]string{"", "Id gt 10", ""}, "Id gt 10"},
+		{[]string{"Id gt 10", "Id lt 20"}, "(Id gt 10) and (Id lt 20)"},
+		{[]string{"Status eq 'A' or Status eq 'B'", "Id lt 20"}, "(Status eq 'A' or Status eq 'B') and (Id lt 20)"},
+	}
+
+	for _, c := range cases {
+		if got := AndFilters(c.filters...); got != c.want {
+			t.Errorf("AndFilters(%q) = %q, want %q", c.filters, got, c.want)
+		}
+	}
+}
+
+func TestODataDateTime(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	got := ODataDateTime(time.Date(2023, 5, 1, 15, 4, 5, 0, loc))
+	if want := "datetime'2023-05-01T12:04:05Z'"; got != want {
+		t.Errorf("ODataDateTime() = %q, want %q", got, want)
+	}
+}
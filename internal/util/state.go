@@ -0,0 +1,20 @@
+package util
+
+import (
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/cloudquery/plugin-sdk/v4/state"
+)
+
+// StateProvider is implemented by the plugin client to share the sync state backend with resolvers
+type StateProvider interface {
+	State() state.Client
+}
+
+// GetState returns the state backend client from the client meta
+// No-op client is returned when the sync has no state backend configured
+func GetState(meta schema.ClientMeta) state.Client {
+	if p, ok := meta.(StateProvider); ok && p.State() != nil {
+		return p.State()
+	}
+	return &state.NoOpClient{}
+}
@@ -18,3 +18,25 @@ func RemoveRelativeURLPrefix(relURL, prefix string) string {
 	}
 	return relURL
 }
+
+// Checks if URL is an absolute one
+func IsAbsoluteURL(uri string) bool {
+	u := strings.ToLower(uri)
+	return strings.HasPrefix(u, "https://") || strings.HasPrefix(u, "http://")
+}
+
+// Checks if URL is a wildcard pattern
+func IsPattern(uri string) bool {
+	return strings.ContainsAny(uri, "*?[")
+}
+
+// Returns pattern part before the first segment with wildcards
+func StaticPrefix(pattern string) string {
+	segs := strings.Split(pattern, "/")
+	for i, seg := range segs {
+		if IsPattern(seg) {
+			return strings.Join(segs[:i], "/") + "/"
+		}
+	}
+	return pattern
+}
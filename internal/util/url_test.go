@@ -0,0 +1,22 @@
+package util
+
+import "testing"
+
+func TestStaticPrefix(t *testing.T) {
+	cases := []struct {
+		pattern string
+		want    string
+	}{
+		{"/sites/proj-*/lists/projects", "/sites/"},
+		{"/sites/*/lists/*", "/sites/"},
+		{"/sites/hr/lists/task?", "/sites/hr/lists/"},
+		{"/sites/hr/[ab]*/lists/projects", "/sites/hr/"},
+		{"/sites/hr/lists/projects", "/sites/hr/lists/projects"},
+	}
+
+	for _, c := range cases {
+		if got := StaticPrefix(c.pattern); got != c.want {
+			t.Errorf("StaticPrefix(%q) = %q, want %q", c.pattern, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/koltyakov/gosip/api"
+)
+
+// Gets web object by its absolute URL
+func GetWeb(sp *api.SP, webURL string) *api.Web {
+	return sp.Web().FromURL(fmt.Sprintf("%s/_api/Web", webURL))
+}
+
+// Gets absolute URLs of a web and all its subwebs recursively
+func GetWebs(sp *api.SP, webURL string) ([]string, error) {
+	return getWebs(sp, webURL, nil)
+}
+
+// Gets absolute URLs of a web and all its subwebs recursively skipping subwebs which can't be read
+// A subweb is still returned when its subwebs can't be read, errors are passed to onError
+func GetAccessibleWebs(sp *api.SP, webURL string, onError func(webURL string, err error)) ([]string, error) {
+	return getWebs(sp, webURL, onError)
+}
+
+func getWebs(sp *api.SP, webURL string, onError func(webURL string, err error)) ([]string, error) {
+	web := GetWeb(sp, webURL)
+
+	resp, err := web.Webs().Select("Url,Webs/Url").Expand("Webs").Top(5000).Get()
+	if err != nil {
+		return nil, err
+	}
+
+	var webs []struct {
+		URL  string `json:"Url"`
+		Webs []struct {
+			URL string `json:"Url"`
+		} `json:"Webs"`
+	}
+
+	if err := json.Unmarshal(resp.Normalized(), &webs); err != nil {
+		return nil, err
+	}
+
+	webURLs := []string{webURL}
+	for _, web := range webs {
+		webURLs = append(webURLs, web.URL)
+		for _, subWeb := range web.Webs {
+			subWebs, err := getWebs(sp, subWeb.URL, onError)
+			if err != nil {
+				if onError == nil {
+					return nil, err
+				}
+				onError(subWeb.URL, err)
+				subWebs = []string{subWeb.URL}
+			}
+			webURLs = append(webURLs, subWebs...)
+		}
+	}
+
+	return webURLs, nil
+}
+
+// Gets absolute URLs of site collections which URL starts with a prefix using Search API
+func GetSiteCollections(sp *api.SP, urlPrefix string) ([]string, error) {
+	rowLimit := 500
+	startRow := 0
+
+	siteURLs := []string{}
+	for {
+		resp, err := sp.Search().PostQuery(&api.SearchQuery{
+			QueryText:        fmt.Sprintf("contentclass:STS_Site Path:\"%s*\"", strings.TrimSuffix(urlPrefix, "/")),
+			SelectProperties: []string{"Path"},
+			TrimDuplicates:   false,
+			StartRow:         startRow,
+			RowLimit:         rowLimit,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		rows := resp.Data().PrimaryQueryResult.RelevantResults.Table.Rows
+		for _, row := range rows {
+			for _, cell := range row.Cells {
+				if cell.Key == "Path" {
+					siteURLs = append(siteURLs, cell.Value)
+				}
+			}
+		}
+
+		if len(rows) < rowLimit {
+			break
+		}
+		startRow += rowLimit
+	}
+
+	return siteURLs, nil
+}
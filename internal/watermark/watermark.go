@@ -0,0 +1,81 @@
+package watermark
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudquery/plugin-sdk/v4/state"
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+)
+
+// Watermark tracks the latest `Modified` value seen within a list sync
+// Tracking is safe for concurrent partitions
+type Watermark struct {
+	key   string
+	start time.Time
+	last  time.Time
+	mu    sync.Mutex
+}
+
+// Get reads persisted `Modified` watermark from the state backend
+func Get(ctx context.Context, stateClient state.Client, key string) (*Watermark, error) {
+	value, err := stateClient.GetKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state key \"%s\": %w", key, err)
+	}
+
+	w := &Watermark{key: key}
+	if value == "" {
+		return w, nil
+	}
+
+	w.start, err = time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse state key \"%s\" value \"%s\": %w", key, value, err)
+	}
+	w.last = w.start
+
+	return w, nil
+}
+
+// Filter returns OData filter for items modified since the watermark minus the overlap window
+func (w *Watermark) Filter(overlap int) string {
+	if w.start.IsZero() {
+		return ""
+	}
+	since := w.start.Add(-time.Duration(overlap) * time.Minute)
+	return "Modified ge " + util.ODataDateTime(since)
+}
+
+// Track moves the watermark forward based on items `Modified` values
+func (w *Watermark) Track(items []map[string]any) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, item := range items {
+		value, ok := item["Modified"].(string)
+		if !ok {
+			continue
+		}
+		modified, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			continue
+		}
+		if modified.After(w.last) {
+			w.last = modified
+		}
+	}
+}
+
+// Save persists the watermark to the state backend when it was moved forward
+func (w *Watermark) Save(ctx context.Context, stateClient state.Client) error {
+	if !w.last.After(w.start) {
+		return nil
+	}
+	if err := stateClient.SetKey(ctx, w.key, w.last.UTC().Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("failed to set state key \"%s\": %w", w.key, err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package watermark
+
+import (
+	"context"
+	"testing"
+)
+
+// memState is an in-memory state backend
+type memState map[string]string
+
+func (s memState) SetKey(_ context.Context, key string, value string) error {
+	s[key] = value
+	return nil
+}
+
+func (s memState) GetKey(_ context.Context, key string) (string, error) {
+	return s[key], nil
+}
+
+func (memState) Flush(context.Context) error {
+	return nil
+}
+
+func TestZeroWatermark(t *testing.T) {
+	ctx := context.Background()
+	st := memState{}
+
+	w, err := Get(ctx, st, "tasks")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f := w.Filter(5); f != "" {
+		t.Errorf("Filter() = %q, want empty filter for a list without a watermark", f)
+	}
+
+	w.Track([]map[string]any{
+		{"Modified": "2023-05-01T10:00:00Z"},
+		{"Modified": "2023-05-01T12:00:00Z"},
+		{"Modified": "2023-05-01T11:00:00Z"},
+		{"Modified": nil},
+		{"Modified": "not a date"},
+	})
+
+	if err := w.Save(ctx, st); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := st["tasks"], "2023-05-01T12:00:00Z"; got != want {
+		t.Errorf("saved watermark = %q, want %q", got, want)
+	}
+}
+
+func TestWatermarkOverlap(t *testing.T) {
+	ctx := context.Background()
+	st := memState{"tasks": "2023-05-01T12:00:00Z"}
+
+	w, err := Get(ctx, st, "tasks")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		overlap int
+		want    string
+	}{
+		{0, "Modified ge datetime'2023-05-01T12:00:00Z'"},
+		{5, "Modified ge datetime'2023-05-01T11:55:00Z'"},
+		{90, "Modified ge datetime'2023-05-01T10:30:00Z'"},
+	}
+
+	for _, c := range cases {
+		if got := w.Filter(c.overlap); got != c.want {
+			t.Errorf("Filter(%d) = %q, want %q", c.overlap, got, c.want)
+		}
+	}
+}
+
+func TestWatermarkNoAdvance(t *testing.T) {
+	ctx := context.Background()
+	st := memState{"tasks": "2023-05-01T12:00:00Z"}
+
+	w, err := Get(ctx, st, "tasks")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Items within the overlap window don't move the watermark
+	w.Track([]map[string]any{
+		{"Modified": "2023-05-01T11:58:00Z"},
+		{"Modified": "2023-05-01T12:00:00Z"},
+	})
+
+	st["tasks"] = "unchanged"
+	if err := w.Save(ctx, st); err != nil {
+		t.Fatal(err)
+	}
+	if got := st["tasks"]; got != "unchanged" {
+		t.Errorf("saved watermark = %q, want Save to be skipped", got)
+	}
+}
+
+func TestWatermarkInvalidState(t *testing.T) {
+	if _, err := Get(context.Background(), memState{"tasks": "yesterday"}, "tasks"); err == nil {
+		t.Error("Get() error = nil, want error for an invalid state value")
+	}
+}
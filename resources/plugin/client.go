@@ -8,15 +8,23 @@ import (
 	"github.com/cloudquery/plugin-sdk/v4/plugin"
 	"github.com/cloudquery/plugin-sdk/v4/scheduler"
 	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/cloudquery/plugin-sdk/v4/state"
 	"github.com/koltyakov/cq-source-sharepoint/resources/auth"
+	"github.com/koltyakov/cq-source-sharepoint/resources/services/drift"
 	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
+const maxMsgSize = 100 * 1024 * 1024 // 100 MiB
+
 type Client struct {
 	logger    zerolog.Logger
 	spec      Spec
 	tables    schema.Tables
 	scheduler *scheduler.Scheduler
+	state     state.Client
+	drift     *drift.Report
 
 	options plugin.NewClientOptions
 
@@ -26,6 +34,12 @@ type Client struct {
 func (*Client) ID() string {
 	return Name
 }
+
+// State returns sync state backend client, used by incremental tables
+func (c *Client) State() state.Client {
+	return c.state
+}
+
 func (c *Client) Sync(ctx context.Context, options plugin.SyncOptions, res chan<- message.SyncMessage) error {
 	if c.options.NoConnection {
 		return fmt.Errorf("no connection")
@@ -41,7 +55,38 @@ func (c *Client) Sync(ctx context.Context, options plugin.SyncOptions, res chan<
 		return err
 	}
 
-	return c.scheduler.Sync(ctx, c, tt, res, scheduler.WithSyncDeterministicCQID(options.DeterministicCQID))
+	c.state = &state.NoOpClient{}
+	if options.BackendOptions != nil {
+		conn, err := grpc.DialContext(ctx, options.BackendOptions.Connection,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithDefaultCallOptions(
+				grpc.MaxCallRecvMsgSize(maxMsgSize),
+				grpc.MaxCallSendMsgSize(maxMsgSize),
+			),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to dial state backend at %s: %w", options.BackendOptions.Connection, err)
+		}
+		defer conn.Close()
+
+		c.state, err = state.NewClient(ctx, conn, options.BackendOptions.TableName)
+		if err != nil {
+			return fmt.Errorf("failed to create state client: %w", err)
+		}
+	}
+
+	// Schema drift against the previous sync is checked before any table is synced
+	if c.drift != nil {
+		if err := c.drift.Check(ctx, c.state); err != nil {
+			return err
+		}
+	}
+
+	if err := c.scheduler.Sync(ctx, c, tt, res, scheduler.WithSyncDeterministicCQID(options.DeterministicCQID)); err != nil {
+		return err
+	}
+
+	return c.state.Flush(ctx)
 }
 
 func (c *Client) Tables(_ context.Context, options plugin.TableOptions) (schema.Tables, error) {
@@ -87,7 +132,7 @@ func NewClient(_ context.Context, logger zerolog.Logger, cnfg []byte, opts plugi
 		return nil, fmt.Errorf("failed to connect to SharePoint: %w", err)
 	}
 
-	tables, err := spec.getTables(sp, logger)
+	tables, report, err := spec.getTables(sp, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve tables: %w", err)
 	}
@@ -96,6 +141,7 @@ func NewClient(_ context.Context, logger zerolog.Logger, cnfg []byte, opts plugi
 		logger:    logger,
 		spec:      *spec,
 		tables:    tables,
+		drift:     report,
 		scheduler: scheduler.NewScheduler(scheduler.WithLogger(logger)),
 		options:   opts,
 	}, nil
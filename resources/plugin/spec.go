@@ -5,11 +5,17 @@ import (
 	"fmt"
 
 	"github.com/koltyakov/cq-source-sharepoint/resources/auth"
+	"github.com/koltyakov/cq-source-sharepoint/resources/services/catalog"
+	"github.com/koltyakov/cq-source-sharepoint/resources/services/changes"
 	"github.com/koltyakov/cq-source-sharepoint/resources/services/ct"
+	"github.com/koltyakov/cq-source-sharepoint/resources/services/drift"
 	"github.com/koltyakov/cq-source-sharepoint/resources/services/lists"
 	"github.com/koltyakov/cq-source-sharepoint/resources/services/mmd"
 	"github.com/koltyakov/cq-source-sharepoint/resources/services/profiles"
+	"github.com/koltyakov/cq-source-sharepoint/resources/services/recyclebin"
 	"github.com/koltyakov/cq-source-sharepoint/resources/services/search"
+	"github.com/koltyakov/cq-source-sharepoint/resources/services/security"
+	"github.com/koltyakov/cq-source-sharepoint/resources/services/webs"
 )
 
 // Spec is the configuration for a SharePoint source
@@ -18,9 +24,14 @@ type Spec struct {
 	Auth auth.Spec `json:"auth"`
 
 	// A map of URIs to the list configuration
+	// Keys are list URIs relative to the site, absolute or server relative list URLs,
+	// or wildcard patterns (e.g. `sites/proj-*/Lists/Projects`) matching lists across sites
 	// If no lists are provided, nothing will be fetched
 	Lists map[string]lists.Spec `json:"lists"`
 
+	// Lists inventory configuration
+	ListsInventory lists.InventorySpec `json:"lists_inventory"`
+
 	// A map of TermSets GUIDs to the MMD configuration
 	MMD map[string]mmd.Spec `json:"mmd"`
 
@@ -32,6 +43,26 @@ type Spec struct {
 
 	// Content types based rollup
 	ContentTypes map[string]ct.Spec `json:"content_types"`
+
+	// Webs inventory configuration
+	Webs webs.Spec `json:"webs"`
+
+	// Fields and content types catalog configuration
+	Catalog catalog.Spec `json:"catalog"`
+
+	// Site users, groups and permissions configuration
+	Security security.Spec `json:"security"`
+
+	// Recycle bin configuration
+	RecycleBin recyclebin.Spec `json:"recycle_bin"`
+
+	// Site change log configuration
+	Changes changes.Spec `json:"changes"`
+
+	// Optional, lists schema drift policy: `fail`, `warn` or `adapt`
+	// Drift findings are recorded to `sharepoint_schema_drift` table
+	// If not provided, schema drift is not detected
+	SchemaDrift string `json:"schema_drift"`
 }
 
 // SetDefaults sets default values for top level spec
@@ -66,6 +97,21 @@ func (s *Spec) SetDefaults() {
 		ctSpec.SetDefault()
 		s.ContentTypes[ctName] = ctSpec
 	}
+
+	// Set default values for Webs inventory spec
+	s.Webs.SetDefault()
+
+	// Set default values for catalog spec
+	s.Catalog.SetDefault()
+
+	// Set default values for security spec
+	s.Security.SetDefault()
+
+	// Set default values for recycle bin spec
+	s.RecycleBin.SetDefault()
+
+	// Set default values for change log spec
+	s.Changes.SetDefault()
 }
 
 // Validate validates SharePoint source spec validity
@@ -77,10 +123,14 @@ func (s *Spec) Validate() error {
 
 	// App only auth is not supported with search driven sources
 	// ToDo: check other not user context auth strategies
-	if s.Auth.Strategy == "addin" && (s.Profiles.Enabled || len(s.Search) > 0) {
+	if s.Auth.Strategy == "addin" && (s.Profiles.Enabled || len(s.Search) > 0 || s.contentTypesUseSearch()) {
 		return fmt.Errorf("this auth strategy is not supported with search API, see more https://learn.microsoft.com/en-us/sharepoint/dev/solution-guidance/search-api-usage-sharepoint-add-in")
 	}
 
+	if err := s.validateSchemaDrift(); err != nil {
+		return err
+	}
+
 	if err := s.validateAliases(); err != nil {
 		return err
 	}
@@ -101,18 +151,56 @@ func (s *Spec) Validate() error {
 		return err
 	}
 
-	return s.validateContentTypes()
+	if err := s.validateContentTypes(); err != nil {
+		return err
+	}
+
+	if err := s.validateWebs(); err != nil {
+		return err
+	}
+
+	if err := s.validateCatalog(); err != nil {
+		return err
+	}
+
+	if err := s.validateSecurity(); err != nil {
+		return err
+	}
+
+	if err := s.validateRecycleBin(); err != nil {
+		return err
+	}
+
+	return s.validateChanges()
+}
+
+func (s *Spec) contentTypesUseSearch() bool {
+	for _, ctSpec := range s.ContentTypes {
+		if ctSpec.UsesSearch() {
+			return true
+		}
+	}
+	return false
 }
 
 func (s *Spec) validateAliases() error {
 	aliases := make(map[string]bool)
 
+	// Lists companion tables (e.g. `<list>_deletions`) take their aliases as well
 	for listURI, listSpec := range s.Lists {
-		alias := listSpec.GetAlias(listURI)
-		if _, ok := aliases[alias]; ok {
-			return fmt.Errorf("duplicate alias \"%s\" for list \"%s\" configuration", alias, listURI)
+		for _, alias := range listSpec.GetAliases(listURI) {
+			if _, ok := aliases[alias]; ok {
+				return fmt.Errorf("duplicate alias \"%s\" for list \"%s\" configuration", alias, listURI)
+			}
+			aliases[alias] = true
 		}
-		aliases[alias] = true
+	}
+
+	if s.ListsInventory.Enabled {
+		if _, ok := aliases["lists"]; ok {
+			return fmt.Errorf("duplicate alias \"lists\" for lists inventory configuration")
+		}
+		aliases["lists"] = true
 	}
 
 	for terSetID, mmdSpec := range s.MMD {
@@ -147,9 +235,55 @@ func (s *Spec) validateAliases() error {
 		aliases[alias] = true
 	}
 
+	if s.Webs.Enabled {
+		alias := s.Webs.GetAlias()
+		if _, ok := aliases[alias]; ok {
+			return fmt.Errorf("duplicate alias \"%s\" for webs inventory configuration", alias)
+		}
+		aliases[alias] = true
+	}
+
+	for _, alias := range s.Catalog.GetAliases() {
+		if _, ok := aliases[alias]; ok {
+			return fmt.Errorf("duplicate alias \"%s\" for catalog configuration", alias)
+		}
+		aliases[alias] = true
+	}
+
+	for _, alias := range s.Security.GetAliases() {
+		if _, ok := aliases[alias]; ok {
+			return fmt.Errorf("duplicate alias \"%s\" for security configuration", alias)
+		}
+		aliases[alias] = true
+	}
+
+	if s.RecycleBin.Enabled {
+		alias := s.RecycleBin.GetAlias()
+		if _, ok := aliases[alias]; ok {
+			return fmt.Errorf("duplicate alias \"%s\" for recycle bin configuration", alias)
+		}
+		aliases[alias] = true
+	}
+
+	if s.Changes.Enabled {
+		alias := s.Changes.GetAlias()
+		if _, ok := aliases[alias]; ok {
+			return fmt.Errorf("duplicate alias \"%s\" for change log configuration", alias)
+		}
+		aliases[alias] = true
+	}
+
 	return nil
 }
 
+func (s *Spec) validateSchemaDrift() error {
+	switch s.SchemaDrift {
+	case "", drift.PolicyFail, drift.PolicyWarn, drift.PolicyAdapt:
+		return nil
+	}
+	return fmt.Errorf("schema_drift should be one of: %s, %s, %s", drift.PolicyFail, drift.PolicyWarn, drift.PolicyAdapt)
+}
+
 func (s *Spec) validateLists() error {
 	for listURI, listSpec := range s.Lists {
 		if err := listSpec.Validate(); err != nil {
@@ -202,6 +336,47 @@ func (s *Spec) validateContentTypes() error {
 	return nil
 }
 
+func (s *Spec) validateWebs() error {
+	if s.Webs.Enabled {
+		if err := s.Webs.Validate(); err != nil {
+			return fmt.Errorf("webs configuration is invalid: %s", err)
+		}
+	}
+	return nil
+}
+
+func (s *Spec) validateCatalog() error {
+	if err := s.Catalog.Validate(); err != nil {
+		return fmt.Errorf("catalog configuration is invalid: %s", err)
+	}
+	return nil
+}
+
+func (s *Spec) validateSecurity() error {
+	if err := s.Security.Validate(); err != nil {
+		return fmt.Errorf("security configuration is invalid: %s", err)
+	}
+	return nil
+}
+
+func (s *Spec) validateRecycleBin() error {
+	if s.RecycleBin.Enabled {
+		if err := s.RecycleBin.Validate(); err != nil {
+			return fmt.Errorf("recycle bin configuration is invalid: %s", err)
+		}
+	}
+	return nil
+}
+
+func (s *Spec) validateChanges() error {
+	if s.Changes.Enabled {
+		if err := s.Changes.Validate(); err != nil {
+			return fmt.Errorf("change log configuration is invalid: %s", err)
+		}
+	}
+	return nil
+}
+
 // getSpec unmarshals and validates the spec
 func getSpec(src []byte) (*Spec, error) {
 	var spec *Spec
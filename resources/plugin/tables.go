@@ -5,73 +5,126 @@ import (
 
 	"github.com/cloudquery/plugin-sdk/v4/schema"
 	"github.com/cloudquery/plugin-sdk/v4/transformers"
+	"github.com/koltyakov/cq-source-sharepoint/resources/services/catalog"
+	"github.com/koltyakov/cq-source-sharepoint/resources/services/changes"
 	"github.com/koltyakov/cq-source-sharepoint/resources/services/ct"
+	"github.com/koltyakov/cq-source-sharepoint/resources/services/drift"
 	"github.com/koltyakov/cq-source-sharepoint/resources/services/lists"
 	"github.com/koltyakov/cq-source-sharepoint/resources/services/mmd"
 	"github.com/koltyakov/cq-source-sharepoint/resources/services/profiles"
+	"github.com/koltyakov/cq-source-sharepoint/resources/services/recyclebin"
 	"github.com/koltyakov/cq-source-sharepoint/resources/services/search"
+	"github.com/koltyakov/cq-source-sharepoint/resources/services/security"
+	"github.com/koltyakov/cq-source-sharepoint/resources/services/webs"
 	"github.com/koltyakov/gosip/api"
 	"github.com/rs/zerolog"
 )
 
-func (s *Spec) getTables(sp *api.SP, logger zerolog.Logger) (schema.Tables, error) {
+// getTables builds tables from the spec, schema drift report is returned when drift detection is enabled
+func (s *Spec) getTables(sp *api.SP, logger zerolog.Logger) (schema.Tables, *drift.Report, error) {
 	tables := schema.Tables{}
 
+	// Schema drift report is filled while lists tables are built
+	var report *drift.Report
+	if s.SchemaDrift != "" {
+		report = drift.NewReport(s.SchemaDrift, logger)
+	}
+
 	// Tables from lists config
-	listTables, err := s.getListsTables(sp, logger)
+	listTables, err := s.getListsTables(sp, logger, report)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	tables = append(tables, listTables...)
 
+	if report != nil {
+		if err := report.Err(); err != nil {
+			return nil, nil, err
+		}
+		tables = append(tables, report.GetDestTable())
+	}
+
 	// Tables from mmd config
 	mmdTables, err := s.getMMDTables(sp, logger)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	tables = append(tables, mmdTables...)
 
 	// Tables from profiles config
 	profileTables, err := s.getProfileTables(sp, logger)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	tables = append(tables, profileTables...)
 
 	// Tables from search config
 	searchTables, err := s.getSearchTables(sp, logger)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	tables = append(tables, searchTables...)
 
 	// Tables from content types config
 	ctTables, err := s.getContentTypeTables(sp, logger)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	tables = append(tables, ctTables...)
 
+	// Tables from webs inventory config
+	websTables, err := s.getWebsTables(sp, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	tables = append(tables, websTables...)
+
+	// Tables from fields and content types catalog config
+	tables = append(tables, s.getCatalogTables(sp, logger)...)
+
+	// Tables from security config
+	tables = append(tables, s.getSecurityTables(sp, logger)...)
+
+	// Tables from recycle bin config
+	recycleBinTables, err := s.getRecycleBinTables(sp, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	tables = append(tables, recycleBinTables...)
+
+	// Tables from change log config
+	changesTables, err := s.getChangesTables(sp, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	tables = append(tables, changesTables...)
+
 	if err := transformers.TransformTables(tables); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	for _, table := range tables {
 		schema.AddCqIDs(table)
 	}
 
-	return tables, nil
+	return tables, report, nil
 }
 
-func (s *Spec) getListsTables(sp *api.SP, logger zerolog.Logger) (schema.Tables, error) {
+func (s *Spec) getListsTables(sp *api.SP, logger zerolog.Logger, report *drift.Report) (schema.Tables, error) {
 	tables := make(schema.Tables, 0, len(s.Lists))
-	l := lists.NewLists(sp, logger)
+	l := lists.NewLists(sp, logger, report)
 	for uri, spec := range s.Lists {
 		table, err := l.GetDestTable(uri, spec)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get list '%s': %w", uri, err)
 		}
 		tables = append(tables, table)
+		if spec.Deletions {
+			tables = append(tables, l.GetDeletionsTable(uri, table))
+		}
+	}
+	if s.ListsInventory.Enabled {
+		tables = append(tables, l.GetInventoryTable(s.ListsInventory))
 	}
 	return tables, nil
 }
@@ -126,3 +179,69 @@ func (s *Spec) getContentTypeTables(sp *api.SP, logger zerolog.Logger) (schema.T
 	}
 	return tables, nil
 }
+
+func (s *Spec) getWebsTables(sp *api.SP, logger zerolog.Logger) (schema.Tables, error) {
+	if !s.Webs.Enabled {
+		return nil, nil
+	}
+
+	table, err := webs.NewWebs(sp, logger).GetDestTable(s.Webs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webs: %w", err)
+	}
+	return schema.Tables{table}, nil
+}
+
+func (s *Spec) getCatalogTables(sp *api.SP, logger zerolog.Logger) schema.Tables {
+	tables := schema.Tables{}
+	c := catalog.NewCatalog(sp, logger)
+	if s.Catalog.Fields {
+		tables = append(tables, c.GetFieldsTable(s.Catalog))
+	}
+	if s.Catalog.ContentTypes {
+		tables = append(tables, c.GetContentTypesTable(s.Catalog))
+	}
+	return tables
+}
+
+func (s *Spec) getSecurityTables(sp *api.SP, logger zerolog.Logger) schema.Tables {
+	tables := schema.Tables{}
+	sec := security.NewSecurity(sp, logger)
+	if s.Security.Users {
+		tables = append(tables, sec.GetUsersTable())
+	}
+	if s.Security.Groups {
+		tables = append(tables, sec.GetGroupsTable())
+	}
+	if s.Security.RoleDefinitions {
+		tables = append(tables, sec.GetRoleDefinitionsTable())
+	}
+	if s.Security.RoleAssignments {
+		tables = append(tables, sec.GetRoleAssignmentsTable())
+	}
+	return tables
+}
+
+func (s *Spec) getRecycleBinTables(sp *api.SP, logger zerolog.Logger) (schema.Tables, error) {
+	if !s.RecycleBin.Enabled {
+		return nil, nil
+	}
+
+	table, err := recyclebin.NewRecycleBin(sp, logger).GetDestTable(s.RecycleBin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recycle bin: %w", err)
+	}
+	return schema.Tables{table}, nil
+}
+
+func (s *Spec) getChangesTables(sp *api.SP, logger zerolog.Logger) (schema.Tables, error) {
+	if !s.Changes.Enabled {
+		return nil, nil
+	}
+
+	table, err := changes.NewChanges(sp, logger).GetDestTable(s.Changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changes: %w", err)
+	}
+	return schema.Tables{table}, nil
+}
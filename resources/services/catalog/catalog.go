@@ -0,0 +1,107 @@
+package catalog
+
+import (
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/koltyakov/gosip/api"
+	"github.com/rs/zerolog"
+)
+
+type Catalog struct {
+	sp     *api.SP
+	logger zerolog.Logger
+}
+
+func NewCatalog(sp *api.SP, logger zerolog.Logger) *Catalog {
+	return &Catalog{
+		sp:     sp,
+		logger: logger,
+	}
+}
+
+type fieldInfo struct {
+	ID                 string `json:"Id"`
+	InternalName       string `json:"InternalName"`
+	EntityPropertyName string `json:"EntityPropertyName"`
+	Title              string `json:"Title"`
+	Description        string `json:"Description"`
+	TypeAsString       string `json:"TypeAsString"`
+	FieldTypeKind      int    `json:"FieldTypeKind"`
+	Group              string `json:"Group"`
+	Hidden             bool   `json:"Hidden"`
+	Required           bool   `json:"Required"`
+	ReadOnlyField      bool   `json:"ReadOnlyField"`
+	Indexed            bool   `json:"Indexed"`
+
+	WebURL string `json:"-"`
+	ListID string `json:"-"`
+}
+
+var fieldProps = []string{
+	"Id", "InternalName", "EntityPropertyName", "Title", "Description", "TypeAsString", "FieldTypeKind",
+	"Group", "Hidden", "Required", "ReadOnlyField", "Indexed",
+}
+
+type contentTypeInfo struct {
+	ID          string `json:"StringId"`
+	Name        string `json:"Name"`
+	Description string `json:"Description"`
+	Group       string `json:"Group"`
+	Hidden      bool   `json:"Hidden"`
+	ReadOnly    bool   `json:"ReadOnly"`
+	Sealed      bool   `json:"Sealed"`
+	Parent      struct {
+		ID string `json:"StringId"`
+	} `json:"Parent"`
+
+	WebURL string `json:"-"`
+	ListID string `json:"-"`
+}
+
+var contentTypeProps = []string{"StringId", "Name", "Description", "Group", "Hidden", "ReadOnly", "Sealed", "Parent/StringId"}
+
+// GetFieldsTable returns site and list columns catalog table
+func (c *Catalog) GetFieldsTable(spec Spec) *schema.Table {
+	return &schema.Table{
+		Name:        "sharepoint_fields",
+		Description: "Site and list columns",
+		Columns: []schema.Column{
+			{Name: "web_url", Type: arrow.BinaryTypes.String, PrimaryKey: true, Resolver: schema.PathResolver("WebURL")},
+			{Name: "list_id", Type: arrow.BinaryTypes.String, PrimaryKey: true, Description: "Empty for site columns", Resolver: schema.PathResolver("ListID")},
+			{Name: "id", Type: arrow.BinaryTypes.String, PrimaryKey: true, Resolver: schema.PathResolver("ID")},
+			{Name: "internal_name", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("InternalName")},
+			{Name: "entity_property_name", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("EntityPropertyName")},
+			{Name: "title", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Title")},
+			{Name: "description", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Description")},
+			{Name: "type", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("TypeAsString")},
+			{Name: "kind", Type: arrow.PrimitiveTypes.Int32, Resolver: schema.PathResolver("FieldTypeKind")},
+			{Name: "group", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Group")},
+			{Name: "hidden", Type: arrow.FixedWidthTypes.Boolean, Resolver: schema.PathResolver("Hidden")},
+			{Name: "required", Type: arrow.FixedWidthTypes.Boolean, Resolver: schema.PathResolver("Required")},
+			{Name: "read_only", Type: arrow.FixedWidthTypes.Boolean, Resolver: schema.PathResolver("ReadOnlyField")},
+			{Name: "indexed", Type: arrow.FixedWidthTypes.Boolean, Resolver: schema.PathResolver("Indexed")},
+		},
+		Resolver: c.FieldsResolver(spec),
+	}
+}
+
+// GetContentTypesTable returns site and list content types catalog table
+func (c *Catalog) GetContentTypesTable(spec Spec) *schema.Table {
+	return &schema.Table{
+		Name:        "sharepoint_content_types",
+		Description: "Site and list content types",
+		Columns: []schema.Column{
+			{Name: "web_url", Type: arrow.BinaryTypes.String, PrimaryKey: true, Resolver: schema.PathResolver("WebURL")},
+			{Name: "list_id", Type: arrow.BinaryTypes.String, PrimaryKey: true, Description: "Empty for site content types", Resolver: schema.PathResolver("ListID")},
+			{Name: "id", Type: arrow.BinaryTypes.String, PrimaryKey: true, Resolver: schema.PathResolver("ID")},
+			{Name: "name", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Name")},
+			{Name: "description", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Description")},
+			{Name: "group", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Group")},
+			{Name: "hidden", Type: arrow.FixedWidthTypes.Boolean, Resolver: schema.PathResolver("Hidden")},
+			{Name: "read_only", Type: arrow.FixedWidthTypes.Boolean, Resolver: schema.PathResolver("ReadOnly")},
+			{Name: "sealed", Type: arrow.FixedWidthTypes.Boolean, Resolver: schema.PathResolver("Sealed")},
+			{Name: "parent_id", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Parent.ID")},
+		},
+		Resolver: c.ContentTypesResolver(spec),
+	}
+}
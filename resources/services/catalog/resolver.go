@@ -0,0 +1,144 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+	"github.com/koltyakov/gosip/api"
+)
+
+type ResolverClosure = func(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error
+
+func (c *Catalog) FieldsResolver(spec Spec) ResolverClosure {
+	return func(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error {
+		return c.forEachSource(spec, func(webURL string, listID string, web *api.Web) error {
+			fields := web.Fields()
+			if listID != "" {
+				fields = web.Lists().GetByID(listID).Fields()
+			}
+
+			resp, err := fields.Select(strings.Join(fieldProps, ",")).Top(5000).Get()
+			if err != nil {
+				return fmt.Errorf("failed to get fields: %w", err)
+			}
+
+			var infos []*fieldInfo
+			if err := json.Unmarshal(resp.Normalized(), &infos); err != nil {
+				return fmt.Errorf("failed to unmarshal fields: %w", err)
+			}
+
+			for _, info := range infos {
+				info.WebURL = webURL
+				info.ListID = listID
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case res <- info:
+				}
+			}
+
+			return nil
+		})
+	}
+}
+
+func (c *Catalog) ContentTypesResolver(spec Spec) ResolverClosure {
+	return func(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error {
+		return c.forEachSource(spec, func(webURL string, listID string, web *api.Web) error {
+			contentTypes := web.ContentTypes()
+			if listID != "" {
+				contentTypes = web.Lists().GetByID(listID).ContentTypes()
+			}
+
+			resp, err := contentTypes.Select(strings.Join(contentTypeProps, ",")).Expand("Parent").Top(5000).Get()
+			if err != nil {
+				return fmt.Errorf("failed to get content types: %w", err)
+			}
+
+			var infos []*contentTypeInfo
+			if err := json.Unmarshal(resp.Normalized(), &infos); err != nil {
+				return fmt.Errorf("failed to unmarshal content types: %w", err)
+			}
+
+			for _, info := range infos {
+				info.WebURL = webURL
+				info.ListID = listID
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case res <- info:
+				}
+			}
+
+			return nil
+		})
+	}
+}
+
+// forEachSource calls fn for the context web, its subwebs and their lists according to the spec
+// listID is empty for web level sources
+func (c *Catalog) forEachSource(spec Spec, fn func(webURL string, listID string, web *api.Web) error) error {
+	webURLs := []string{c.sp.ToURL()}
+	// Subwebs which can't be read are skipped together with their subwebs
+	skipped := map[string]bool{}
+	if spec.Subwebs {
+		var err error
+		webURLs, err = util.GetAccessibleWebs(c.sp, c.sp.ToURL(), func(webURL string, err error) {
+			c.logger.Warn().Err(err).Str("web", webURL).Msg("failed to get subwebs, skipping")
+			skipped[webURL] = true
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get webs: %w", err)
+		}
+	}
+
+	for _, webURL := range webURLs {
+		if skipped[webURL] {
+			continue
+		}
+		web := util.GetWeb(c.sp, webURL)
+		if err := fn(webURL, "", web); err != nil {
+			return err
+		}
+
+		if !spec.Lists {
+			continue
+		}
+
+		listIDs, err := getListIDs(web)
+		if err != nil {
+			return err
+		}
+		for _, listID := range listIDs {
+			if err := fn(webURL, listID, web); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func getListIDs(web *api.Web) ([]string, error) {
+	resp, err := web.Lists().Select("Id").Filter("Hidden eq false").Top(5000).Get()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lists: %w", err)
+	}
+
+	var lists []struct {
+		ID string `json:"Id"`
+	}
+	if err := json.Unmarshal(resp.Normalized(), &lists); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal lists: %w", err)
+	}
+
+	listIDs := make([]string, len(lists))
+	for i, list := range lists {
+		listIDs[i] = list.ID
+	}
+	return listIDs, nil
+}
@@ -0,0 +1,37 @@
+package catalog
+
+// Spec is the configuration for fields and content types catalog source
+type Spec struct {
+	// Whether to sync fields to `sharepoint_fields` table
+	Fields bool `json:"fields"`
+	// Whether to sync content types to `sharepoint_content_types` table
+	ContentTypes bool `json:"content_types"`
+	// Optional, whether to include fields and content types of lists
+	// If not provided, only site columns and site content types are synced
+	Lists bool `json:"lists"`
+	// Optional, whether to include fields and content types of all subwebs recursively
+	Subwebs bool `json:"subwebs"`
+}
+
+// SetDefault sets default values for catalog spec
+func (*Spec) SetDefault() {
+	// Default values
+}
+
+// Validate validates catalog spec validity
+func (*Spec) Validate() error {
+	// Nothing to validate
+	return nil
+}
+
+// GetAliases returns the aliases for catalog tables
+func (s *Spec) GetAliases() []string {
+	var aliases []string
+	if s.Fields {
+		aliases = append(aliases, "fields")
+	}
+	if s.ContentTypes {
+		aliases = append(aliases, "content_types")
+	}
+	return aliases
+}
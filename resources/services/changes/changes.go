@@ -0,0 +1,87 @@
+package changes
+
+import (
+	"context"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+	"github.com/koltyakov/gosip/api"
+	"github.com/rs/zerolog"
+)
+
+type Changes struct {
+	sp     *api.SP
+	logger zerolog.Logger
+}
+
+func NewChanges(sp *api.SP, logger zerolog.Logger) *Changes {
+	return &Changes{
+		sp:     sp,
+		logger: logger,
+	}
+}
+
+// changeTypes are names of `SP.ChangeType` values
+var changeTypes = map[int]string{
+	1:  "Add",
+	2:  "Update",
+	3:  "DeleteObject",
+	4:  "Rename",
+	5:  "MoveAway",
+	6:  "MoveInto",
+	7:  "Restore",
+	8:  "RoleAdd",
+	9:  "RoleDelete",
+	10: "RoleUpdate",
+	11: "AssignmentAdd",
+	12: "AssignmentDelete",
+	13: "MemberAdd",
+	14: "MemberDelete",
+	15: "SystemUpdate",
+	16: "Navigation",
+	17: "ScopeAdd",
+	18: "ScopeDelete",
+	19: "ListContentTypeAdd",
+	20: "ListContentTypeDelete",
+	21: "Dirty",
+	22: "Activity",
+}
+
+func (c *Changes) GetDestTable(spec Spec) (*schema.Table, error) {
+	return &schema.Table{
+		Name:          "sharepoint_changes",
+		Description:   "Site change log",
+		IsIncremental: true,
+		Columns: []schema.Column{
+			{Name: "change_token", Type: arrow.BinaryTypes.String, PrimaryKey: true, IncrementalKey: true, Resolver: propResolver("ChangeToken/StringValue")},
+			{Name: "object_type", Type: arrow.BinaryTypes.String, PrimaryKey: true, Resolver: propResolver(objectTypeProp)},
+			{Name: "change_type", Type: arrow.PrimitiveTypes.Int32, Resolver: propResolver("ChangeType")},
+			{Name: "change_type_name", Type: arrow.BinaryTypes.String, Resolver: propResolver(changeTypeNameProp)},
+			{Name: "site_id", Type: arrow.BinaryTypes.String, Resolver: propResolver("SiteId")},
+			{Name: "web_id", Type: arrow.BinaryTypes.String, Resolver: propResolver("WebId")},
+			{Name: "list_id", Type: arrow.BinaryTypes.String, Resolver: propResolver("ListId")},
+			{Name: "item_id", Type: arrow.PrimitiveTypes.Int32, Resolver: propResolver("ItemId")},
+			{Name: "unique_id", Type: arrow.BinaryTypes.String, Resolver: propResolver("UniqueId")},
+			{Name: "change_time", Type: arrow.FixedWidthTypes.Timestamp_us, Resolver: propResolver("Time")},
+		},
+		Resolver: c.Resolver(spec),
+	}, nil
+}
+
+// Props injected into changes
+const (
+	objectTypeProp     = "@ObjectType"
+	changeTypeNameProp = "@ChangeTypeName"
+)
+
+func propResolver(prop string) schema.ColumnResolver {
+	return func(ctx context.Context, meta schema.ClientMeta, resource *schema.Resource, c schema.Column) error {
+		value := util.GetRespValByProp(resource.Item.(map[string]any), prop)
+		// Empty GUIDs are returned for changes which are not related to a web or a list
+		if value == "" || value == "00000000-0000-0000-0000-000000000000" {
+			value = nil
+		}
+		return resource.Set(c.Name, value)
+	}
+}
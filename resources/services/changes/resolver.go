@@ -0,0 +1,139 @@
+package changes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+	"github.com/koltyakov/gosip/api"
+)
+
+type ResolverClosure = func(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error
+
+const fetchLimit = 1000
+
+func (c *Changes) Resolver(spec Spec) ResolverClosure {
+	return func(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error {
+		// Change log entries don't carry changed object type,
+		// so each object type is queried separately with its own change token
+		for _, objectType := range spec.Objects {
+			if err := c.syncChanges(ctx, meta, spec, objectType, res); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func (c *Changes) syncChanges(ctx context.Context, meta schema.ClientMeta, spec Spec, objectType string, res chan<- any) error {
+	stateClient := util.GetState(meta)
+	stateKey := fmt.Sprintf("sharepoint_changes_%s_%s", spec.Scope, objectType)
+
+	token, err := stateClient.GetKey(ctx, stateKey)
+	if err != nil {
+		return fmt.Errorf("failed to get state key \"%s\": %w", stateKey, err)
+	}
+
+	c.logger.Debug().Str("object_type", objectType).Str("token", token).Msg("reading changes")
+
+	changes, err := c.getChanges(spec).GetChanges(getChangeQuery(objectType, token))
+
+	for {
+		if err != nil {
+			return fmt.Errorf("failed to get %s changes: %w", objectType, err)
+		}
+
+		data := changes.Data()
+		rows := make([]map[string]any, 0, len(data))
+		for _, change := range data {
+			if change.ChangeToken != nil {
+				token = change.ChangeToken.StringValue
+			}
+
+			row, err := toRow(change)
+			if err != nil {
+				return err
+			}
+			row[objectTypeProp] = objectType
+			row[changeTypeNameProp] = changeTypes[change.ChangeType]
+			rows = append(rows, row)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case res <- rows:
+		}
+
+		if len(data) < fetchLimit {
+			break
+		}
+		changes, err = changes.GetNextPage()
+	}
+
+	if token == "" {
+		return nil
+	}
+	if err := stateClient.SetKey(ctx, stateKey, token); err != nil {
+		return fmt.Errorf("failed to set state key \"%s\": %w", stateKey, err)
+	}
+
+	return nil
+}
+
+func (c *Changes) getChanges(spec Spec) *api.Changes {
+	if spec.Scope == "web" {
+		return c.sp.Web().Changes()
+	}
+	return c.sp.Site().Changes()
+}
+
+// getChangeQuery returns a query for all change types of an object type
+func getChangeQuery(objectType string, token string) *api.ChangeQuery {
+	query := &api.ChangeQuery{
+		ChangeTokenStart:      token,
+		FetchLimit:            fetchLimit,
+		Add:                   true,
+		Update:                true,
+		DeleteObject:          true,
+		Rename:                true,
+		Restore:               true,
+		Move:                  true,
+		GroupMembershipAdd:    true,
+		GroupMembershipDelete: true,
+		RoleAssignmentAdd:     true,
+		RoleAssignmentDelete:  true,
+	}
+
+	switch objectType {
+	case "item":
+		query.Item = true
+	case "list":
+		query.List = true
+	case "field":
+		query.Field = true
+	case "user":
+		query.User = true
+	case "group":
+		query.Group = true
+	case "security_policy":
+		query.SecurityPolicy = true
+	}
+
+	return query
+}
+
+// toRow maps a change to a generic row, keys are REST API change props
+func toRow(change *api.ChangeInfo) (map[string]any, error) {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal change: %w", err)
+	}
+	var row map[string]any
+	if err := json.Unmarshal(data, &row); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal change: %w", err)
+	}
+	return row, nil
+}
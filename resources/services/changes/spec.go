@@ -0,0 +1,50 @@
+package changes
+
+import (
+	"fmt"
+
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+)
+
+// Supported changed objects types
+var objectTypes = []string{"item", "list", "field", "user", "group", "security_policy"}
+
+// Spec is the configuration for site change log source
+type Spec struct {
+	// Whether to enable change log sync
+	Enabled bool `json:"enabled"`
+	// Optional, `site` for the site collection change log or `web` for the context web change log
+	// If not provided, `site` will be used
+	Scope string `json:"scope"`
+	// Optional, changed objects types: `item`, `list`, `field`, `user`, `group`, `security_policy`
+	// If not provided, all types are synced
+	Objects []string `json:"objects"`
+}
+
+// SetDefault sets default values for change log spec
+func (s *Spec) SetDefault() {
+	if s.Scope == "" {
+		s.Scope = "site"
+	}
+	if len(s.Objects) == 0 {
+		s.Objects = objectTypes
+	}
+}
+
+// Validate validates change log spec validity
+func (s *Spec) Validate() error {
+	if s.Scope != "site" && s.Scope != "web" {
+		return fmt.Errorf("scope should be either site or web")
+	}
+	for _, object := range s.Objects {
+		if !util.Contains(objectTypes, object) {
+			return fmt.Errorf("unknown object type \"%s\"", object)
+		}
+	}
+	return nil
+}
+
+// GetAlias returns the alias for change log table
+func (*Spec) GetAlias() string {
+	return "changes"
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/apache/arrow/go/v14/arrow"
 	"github.com/cloudquery/plugin-sdk/v4/schema"
 	"github.com/cloudquery/plugin-sdk/v4/types"
+	"github.com/koltyakov/cq-source-sharepoint/internal/fields"
+	"github.com/koltyakov/cq-source-sharepoint/internal/lookups"
 	"github.com/koltyakov/cq-source-sharepoint/internal/util"
 	"github.com/koltyakov/gosip/api"
 	"github.com/rs/zerolog"
@@ -28,7 +30,7 @@ func NewContentTypesRollup(sp *api.SP, logger zerolog.Logger) *ContentTypesRollu
 }
 
 func (c *ContentTypesRollup) GetDestTable(ctID string, spec Spec) (*schema.Table, error) {
-	ctInfo, err := c.getContentTypeInfo(ctID)
+	ctInfo, err := c.getContentTypeInfo(ctID, spec)
 	if err != nil {
 		// ToDo: Decide which design is better to warn and go next or fail a sync
 		// Will stay with a fast fail strateg for now so a user will know about an error immediately
@@ -47,16 +49,38 @@ func (c *ContentTypesRollup) GetDestTable(ctID string, spec Spec) (*schema.Table
 	}
 
 	table := &schema.Table{
-		Name:        "sharepoint_rollup_" + tableName,
-		Description: ctInfo.Description,
+		Name:          "sharepoint_rollup_" + tableName,
+		Description:   ctInfo.Description,
+		IsIncremental: spec.Incremental,
 	}
 
 	// ToDo: Rearchitect table construction logic
 	for _, prop := range spec.Select {
 		col := c.getDestCol(prop, tableName, ctInfo, spec)
+		col.IncrementalKey = spec.Incremental && prop == "Modified"
 		table.Columns = append(table.Columns, col)
 	}
 
+	// Lists from different site collections land in the same table
+	if spec.isCrossSite() && table.Column("site_url") == nil {
+		table.Columns = append(table.Columns, schema.Column{
+			Name:        "site_url",
+			Description: "Site collection URL",
+			Type:        arrow.BinaryTypes.String,
+			Resolver: func(ctx context.Context, meta schema.ClientMeta, resource *schema.Resource, c schema.Column) error {
+				return resource.Set(c.Name, resource.Item.(map[string]any)[siteURLProp])
+			},
+		})
+	}
+
+	if spec.ResolveLookups {
+		spec.lookupFields, err = lookups.GetFields(spec.Select, ctInfo.fieldsData, spec.fieldsMapping)
+		if err != nil {
+			return nil, err
+		}
+		table.Columns = append(table.Columns, lookups.Columns(spec.lookupFields, table)...)
+	}
+
 	table.Resolver = c.Resolver(ctInfo.ID, spec, table)
 
 	return table, nil
@@ -81,8 +105,13 @@ func (c *ContentTypesRollup) getDestCol(prop string, tableName string, ctInfo *c
 		fieldAlias = a
 	}
 
+	var fieldType *fields.Type
+
 	valueResolver := func(ctx context.Context, meta schema.ClientMeta, resource *schema.Resource, c schema.Column) error {
 		value := util.GetRespValByProp(resource.Item.(map[string]any), prop)
+		if fieldType != nil {
+			value = fieldType.Convert(value)
+		}
 		if c.Type == arrow.BinaryTypes.String {
 			if value != nil {
 				value = fmt.Sprintf("%v", value)
@@ -102,7 +131,8 @@ func (c *ContentTypesRollup) getDestCol(prop string, tableName string, ctInfo *c
 	}
 
 	field.InternalName = fieldAlias
-	col := c.columnFromField(field, tableName)
+	var col schema.Column
+	col, fieldType = c.columnFromField(field, tableName)
 	col.Description = prop
 	col.Resolver = valueResolver
 
@@ -119,10 +149,19 @@ type contentTypeInfo struct {
 	Name        string           `json:"Name"`
 	Description string           `json:"Description"`
 	Fields      []*api.FieldInfo `json:"Fields"`
+
+	// Raw fields data with type specific props, e.g. lookups settings
+	fieldsData []byte
 }
 
-func (c *ContentTypesRollup) getContentTypeInfo(ctID string) (*contentTypeInfo, error) {
-	resp, err := c.sp.Web().ContentTypes().
+// getContentTypeInfo resolves the content type from the context site or the hub site
+func (c *ContentTypesRollup) getContentTypeInfo(ctID string, spec Spec) (*contentTypeInfo, error) {
+	web := c.sp.Web()
+	if spec.Hub != "" {
+		web = util.GetWeb(c.sp, strings.TrimSuffix(spec.Hub, "/"))
+	}
+
+	resp, err := web.ContentTypes().
 		Filter(fmt.Sprintf("Name eq '%s' or StringId eq '%s'", ctID, ctID)).
 		Select("StringId,Name,Description").
 		Expand("Fields").
@@ -142,53 +181,38 @@ func (c *ContentTypesRollup) getContentTypeInfo(ctID string) (*contentTypeInfo,
 		return nil, fmt.Errorf("content type not found: %s", ctID)
 	}
 
+	var fieldsInfo []struct {
+		Fields json.RawMessage `json:"Fields"`
+	}
+	if err := json.Unmarshal(resp.Normalized(), &fieldsInfo); err != nil {
+		return nil, err
+	}
+	info[0].fieldsData = fieldsInfo[0].Fields
+
 	return info[0], nil
 }
 
-func (c *ContentTypesRollup) columnFromField(field *api.FieldInfo, tableName string) schema.Column {
+func (c *ContentTypesRollup) columnFromField(field *api.FieldInfo, tableName string) (schema.Column, *fields.Type) {
 	logger := c.logger.With().Str("table", tableName).Logger()
 
 	col := schema.Column{
 		Description: field.Description,
 	}
 
-	switch field.TypeAsString {
-	case "ContentTypeId":
-		col.Type = arrow.BinaryTypes.String
-	case "Text", "Note":
+	fieldType, ok := fields.TypeOf(field)
+	if !ok {
+		logger.Warn().Str("type", field.TypeAsString).Int("kind", field.FieldTypeKind).Str("field_title", field.Title).Str("field_id", field.ID).Msg("unknown type, assuming string")
+	}
+
+	col.Type = fieldType.Arrow
+	// Rollups keep large strings for text fields
+	if field.TypeAsString == "Text" || field.TypeAsString == "Note" {
 		col.Type = arrow.BinaryTypes.LargeString
-	case "Integer", "Counter":
-		col.Type = arrow.PrimitiveTypes.Int32
-	case "Currency":
-		col.Type = arrow.PrimitiveTypes.Float32
-	case "Number":
-		col.Type = arrow.PrimitiveTypes.Float32
-	case "DateTime":
-		col.Type = arrow.FixedWidthTypes.Timestamp_us
-	case "Boolean", "Attachments":
-		col.Type = arrow.FixedWidthTypes.Boolean
-	case "Guid":
-		col.Type = types.UUID
-	case "Lookup", "User":
-		col.Type = arrow.PrimitiveTypes.Int32
-	case "LookupMulti", "UserMulti":
-		col.Type = arrow.ListOf(arrow.PrimitiveTypes.Int32)
-	case "Choice":
-		col.Type = arrow.BinaryTypes.String
-	case "MultiChoice":
-		col.Type = arrow.ListOf(arrow.BinaryTypes.String)
-	case "Computed":
-		col.Type = arrow.BinaryTypes.String
-	case "File":
-		col.Type = arrow.BinaryTypes.String
-	default:
-		logger.Warn().Str("type", field.TypeAsString).Int("kind", field.FieldTypeKind).Str("field_title", field.Title).Str("field_id", field.ID).Msg("unknown type, assuming JSON")
-		col.Type = arrow.BinaryTypes.String
 	}
 
 	col.Name = util.NormalizeEntityName(field.InternalName)
 
-	return col
+	return col, fieldType
 }
 
 func (*ContentTypesRollup) typeFromPropName(prop string) arrow.DataType {
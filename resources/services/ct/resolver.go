@@ -3,89 +3,174 @@ package ct
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/koltyakov/cq-source-sharepoint/internal/lookups"
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+	"github.com/koltyakov/cq-source-sharepoint/internal/watermark"
 	"github.com/koltyakov/gosip/api"
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/errgroup"
 )
 
+const listViewThreshold = 5000
+
 type ResolverClosure = func(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error
 
 func (c *ContentTypesRollup) Resolver(contentTypeID string, spec Spec, table *schema.Table) ResolverClosure {
 	return func(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error {
 		logger := c.logger.With().Str("table", table.Name).Logger()
 
-		logger.Debug().Msgf("getting webs for %s", table.Name)
+		// Lookups cache is shared by all lists of the rollup during a sync
+		lr := lookups.NewResolver(c.sp, logger, spec.lookupFields)
 
-		webUrls, err := c.getWebs(c.sp.ToURL())
+		targets, err := c.getTargets(ctx, contentTypeID, spec, logger)
 		if err != nil {
 			return err
 		}
-		logger.Debug().Msgf("webs found: %v", webUrls)
 
-		// Iterate over all webs
-		for _, webURL := range webUrls {
-			logger.Debug().Msgf("getting lists for %s", webURL)
-			lists, err := c.getLists(webURL, contentTypeID)
-			if err != nil {
-				return err
-			}
-			logger.Debug().Msgf("lists with content type: %v", lists)
+		// Sync lists concurrently, a failed list doesn't stop other lists sync
+		// but the table sync reports an error with all failed lists
+		var mu sync.Mutex
+		var errs []error
 
-			// Iterate over all lists
-			for _, listID := range lists {
-				c.logger.Debug().Msgf("list sync: %s", listID)
-				if err := c.syncList(ctx, webURL, listID, contentTypeID, res, spec); err != nil {
-					return err
+		g, gctx := errgroup.WithContext(ctx)
+		g.SetLimit(spec.Concurrency)
+		for _, t := range targets {
+			t := t
+			g.Go(func() error {
+				logger.Debug().Msgf("list sync: %s", t.ListID)
+				if err := c.syncList(gctx, meta, t, table, contentTypeID, lr, res, spec); err != nil {
+					if gctx.Err() != nil {
+						return err
+					}
+					logger.Error().Err(err).Str("web", t.WebURL).Str("list", t.ListID).Msg("failed to sync list")
+					mu.Lock()
+					errs = append(errs, fmt.Errorf("list %s in %s: %w", t.ListID, t.WebURL, err))
+					mu.Unlock()
 				}
-			}
+				return nil
+			})
+		}
+
+		if err := g.Wait(); err != nil {
+			return err
+		}
+
+		if len(errs) > 0 {
+			return fmt.Errorf("failed to sync %d of %d lists: %w", len(errs), len(targets), errors.Join(errs...))
 		}
 
 		return nil
 	}
 }
 
-func (c *ContentTypesRollup) getWeb(webURL string) *api.Web {
-	return c.sp.Web().FromURL(fmt.Sprintf("%s/_api/Web", webURL))
-}
-
-func (c *ContentTypesRollup) getWebs(webURL string) ([]string, error) {
-	web := c.getWeb(webURL)
-
-	resp, err := web.Webs().Select("Url,Webs/Url").Expand("Webs").Top(5000).Get()
-	if err != nil {
-		return nil, err
+// getTargets discovers lists containing the content type
+func (c *ContentTypesRollup) getTargets(ctx context.Context, ctID string, spec Spec, logger zerolog.Logger) ([]*listTarget, error) {
+	if spec.Discovery == discoverySearch {
+		targets, err := c.searchLists(ctID, spec)
+		if err != nil {
+			return nil, err
+		}
+		logger.Debug().Msgf("lists found with search: %d", len(targets))
+		return targets, nil
 	}
 
-	var webs []struct {
-		URL  string `json:"Url"`
-		Webs []struct {
-			URL string `json:"Url"`
-		} `json:"Webs"`
+	// Context site and its subwebs are scanned unless site collections are provided
+	rootURLs := []string{c.sp.ToURL()}
+	if len(spec.Sites) > 0 {
+		var err error
+		rootURLs, err = c.getSiteURLs(spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve sites: %w", err)
+		}
+		logger.Debug().Msgf("sites found: %v", rootURLs)
 	}
 
-	if err := json.Unmarshal(resp.Normalized(), &webs); err != nil {
-		return nil, err
-	}
+	var targets []*listTarget
+	for _, rootURL := range rootURLs {
+		logger.Debug().Msgf("getting webs of %s", rootURL)
 
-	webURLs := []string{webURL}
-	for _, web := range webs {
-		webURLs = append(webURLs, web.URL)
-		for _, subWeb := range web.Webs {
-			subWebs, err := c.getWebs(subWeb.URL)
-			if err != nil {
+		webUrls, err := util.GetAccessibleWebs(c.sp, rootURL, func(webURL string, err error) {
+			logger.Error().Err(err).Str("web", webURL).Msg("failed to get subwebs, skipping")
+		})
+		if err != nil {
+			if len(spec.Sites) == 0 {
 				return nil, err
 			}
-			webURLs = append(webURLs, subWebs...)
+			logger.Error().Err(err).Str("site", rootURL).Msg("failed to get webs, skipping site")
+			continue
+		}
+		logger.Debug().Msgf("webs found: %v", webUrls)
+
+		siteTargets, err := c.discoverLists(ctx, rootURL, webUrls, ctID, spec, logger)
+		if err != nil {
+			return nil, err
 		}
+		targets = append(targets, siteTargets...)
 	}
 
-	return webURLs, nil
+	return targets, nil
+}
+
+// listTarget is a list containing the content type
+type listTarget struct {
+	SiteURL string
+	WebURL  string
+	ListID  string
+}
+
+// stateKey returns state backend key for the list within a table
+// Rollup lists are keyed by web URL and list ID
+func (t *listTarget) stateKey(tableName string) string {
+	return tableName + "_" + strings.TrimRight(t.WebURL, "/") + "_" + t.ListID
+}
+
+// discoverLists finds lists with the content type in webs concurrently
+// An inaccessible web is logged and skipped
+func (c *ContentTypesRollup) discoverLists(ctx context.Context, siteURL string, webUrls []string, ctID string, spec Spec, logger zerolog.Logger) ([]*listTarget, error) {
+	var mu sync.Mutex
+	var targets []*listTarget
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(spec.Concurrency)
+	for _, webURL := range webUrls {
+		webURL := webURL
+		g.Go(func() error {
+			if gctx.Err() != nil {
+				return gctx.Err()
+			}
+
+			logger.Debug().Msgf("getting lists for %s", webURL)
+			lists, err := c.getLists(webURL, ctID)
+			if err != nil {
+				logger.Error().Err(err).Str("web", webURL).Msg("failed to get lists, skipping web")
+				return nil
+			}
+			logger.Debug().Msgf("lists with content type: %v", lists)
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, listID := range lists {
+				targets = append(targets, &listTarget{SiteURL: siteURL, WebURL: webURL, ListID: listID})
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return targets, nil
 }
 
 func (c *ContentTypesRollup) getLists(webURL string, ctID string) ([]string, error) {
-	web := c.getWeb(webURL)
+	web := util.GetWeb(c.sp, webURL)
 
 	resp, err := web.Lists().
 		Select("Id,ContentTypes/StringId").
@@ -119,46 +204,142 @@ func (c *ContentTypesRollup) getLists(webURL string, ctID string) ([]string, err
 	return listIds, nil
 }
 
-func (c *ContentTypesRollup) syncList(ctx context.Context, webURL string, listID string, ctID string, res chan<- any, spec Spec) error {
-	web := c.getWeb(webURL)
-	list := web.Lists().GetByID(listID)
+func (c *ContentTypesRollup) syncList(ctx context.Context, meta schema.ClientMeta, t *listTarget, table *schema.Table, ctID string, lr *lookups.Resolver, res chan<- any, spec Spec) error {
+	web := util.GetWeb(c.sp, t.WebURL)
+	list := web.Lists().GetByID(t.ListID)
+
+	filter := spec.Filter
+
+	// Lists without a watermark in state (e.g. newly discovered) are synced in full
+	var wm *watermark.Watermark
+	if spec.Incremental {
+		var err error
+		wm, err = watermark.Get(ctx, util.GetState(meta), t.stateKey(table.Name))
+		if err != nil {
+			return err
+		}
+		filter = util.AndFilters(filter, wm.Filter(*spec.IncrementalOverlap))
+	}
+
+	if err := c.syncFiltered(ctx, list, t, ctID, filter, wm, lr, res, spec); err != nil {
+		return err
+	}
+
+	if wm != nil {
+		return wm.Save(ctx, util.GetState(meta))
+	}
+
+	return nil
+}
+
+// syncFiltered syncs list items applying content type predicate on the server side when possible
+func (c *ContentTypesRollup) syncFiltered(ctx context.Context, list *api.List, t *listTarget, ctID string, filter string, wm *watermark.Watermark, lr *lookups.Resolver, res chan<- any, spec Spec) error {
+	if spec.ServerFilter && c.canFilterOnServer(list) {
+		ctFilter := util.AndFilters(fmt.Sprintf("startswith(ContentTypeId,'%s')", ctID), filter)
+		sent, err := c.syncItems(ctx, list, t, ctID, ctFilter, wm, lr, res, spec)
+		// Falling back to in-memory filtering only when nothing is sent yet to avoid duplicates
+		if err == nil || sent || !util.IsThrottled(err) {
+			return err
+		}
+		c.logger.Warn().Err(err).Str("web", t.WebURL).Str("list", t.ListID).Msg("server side content type filter is throttled, falling back to in-memory filtering")
+	}
 
 	// Content type is not applied as filter in query to support lists of any size
 	// it is used to filter results in memory after getting responses
+	_, err := c.syncItems(ctx, list, t, ctID, filter, wm, lr, res, spec)
+	return err
+}
+
+// syncItems pages through list items matching a filter, items are also filtered by content type in memory
+// Returns whether any items were sent
+func (c *ContentTypesRollup) syncItems(ctx context.Context, list *api.List, t *listTarget, ctID string, filter string, wm *watermark.Watermark, lr *lookups.Resolver, res chan<- any, spec Spec) (bool, error) {
+	sent := false
+
 	items, err := list.Items().
 		Select(strings.Join(append(spec.Select, "ContentTypeId"), ",")).
 		Expand(strings.Join(spec.Expand, ",")).
+		Filter(filter).
 		Top(5000).
 		GetPaged()
 
 	for {
 		if err != nil {
-			return fmt.Errorf("failed to get items: %w", err)
+			return sent, fmt.Errorf("failed to get items: %w", err)
 		}
 
 		var itemList []map[string]any
 		if err := json.Unmarshal(items.Items.Normalized(), &itemList); err != nil {
-			return err
+			return sent, err
 		}
 
+		ctItems := make([]map[string]any, 0, len(itemList))
 		for _, itemMap := range itemList {
 			// Filter by content type ID (skip items which content type is doesn't strart with base content type ID)
-			if !strings.HasPrefix(itemMap["ContentTypeId"].(string), ctID) {
-				continue
+			if strings.HasPrefix(itemMap["ContentTypeId"].(string), ctID) {
+				itemMap[siteURLProp] = t.SiteURL
+				ctItems = append(ctItems, itemMap)
 			}
+		}
 
+		if err := lr.Resolve(t.WebURL, t.ListID, ctItems); err != nil {
+			return sent, err
+		}
+
+		for _, itemMap := range ctItems {
 			select {
 			case <-ctx.Done():
-				return ctx.Err()
+				return sent, ctx.Err()
 			case res <- itemMap:
+				sent = true
 			}
 		}
 
+		if wm != nil {
+			wm.Track(itemList)
+		}
+
 		if !items.HasNextPage() {
 			break
 		}
 		items, err = items.GetNextPage()
 	}
 
-	return nil
+	return sent, nil
+}
+
+// canFilterOnServer checks if a list can be filtered by content type without hitting the list view threshold:
+// the list is under the threshold or `ContentTypeId` column is indexed
+func (c *ContentTypesRollup) canFilterOnServer(list *api.List) bool {
+	resp, err := list.Select("ItemCount").Get()
+	if err != nil {
+		c.logger.Debug().Err(err).Msg("failed to get list items count, filtering in memory")
+		return false
+	}
+
+	var info struct {
+		ItemCount int `json:"ItemCount"`
+	}
+	if err := json.Unmarshal(resp.Normalized(), &info); err != nil {
+		c.logger.Debug().Err(err).Msg("failed to unmarshal list items count, filtering in memory")
+		return false
+	}
+	if info.ItemCount <= listViewThreshold {
+		return true
+	}
+
+	fieldsResp, err := list.Fields().Filter("InternalName eq 'ContentTypeId'").Select("Indexed").Get()
+	if err != nil {
+		c.logger.Debug().Err(err).Msg("failed to get content type field, filtering in memory")
+		return false
+	}
+
+	var fields []struct {
+		Indexed bool `json:"Indexed"`
+	}
+	if err := json.Unmarshal(fieldsResp.Normalized(), &fields); err != nil {
+		c.logger.Debug().Err(err).Msg("failed to unmarshal content type field, filtering in memory")
+		return false
+	}
+
+	return len(fields) > 0 && fields[0].Indexed
 }
@@ -0,0 +1,83 @@
+package ct
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+	"github.com/koltyakov/gosip/api"
+)
+
+// searchLists finds lists with items of the content type using Search API
+// Search is scoped to the spec sites when provided, otherwise lists are searched across the tenant
+// Items of content types published from the content type hub share the hub content type ID prefix in all sites
+// Items are collapsed by list, so only one result per list is returned and paged through
+func (c *ContentTypesRollup) searchLists(ctID string, spec Spec) ([]*listTarget, error) {
+	queryText := searchQueryText(ctID, spec.Sites)
+
+	rowLimit := 500
+	startRow := 0
+
+	var targets []*listTarget
+	found := map[string]bool{}
+	for {
+		resp, err := c.sp.Search().PostQuery(&api.SearchQuery{
+			QueryText:             queryText,
+			SelectProperties:      []string{"ListId", "SPWebUrl", "SPSiteUrl"},
+			CollapseSpecification: "ListId:1",
+			TrimDuplicates:        false,
+			StartRow:              startRow,
+			RowLimit:              rowLimit,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search lists: %w", err)
+		}
+
+		rows := resp.Data().PrimaryQueryResult.RelevantResults.Table.Rows
+		for _, row := range rows {
+			t := &listTarget{}
+			for _, cell := range row.Cells {
+				switch cell.Key {
+				case "ListId":
+					t.ListID = strings.Trim(cell.Value, "{}")
+				case "SPWebUrl":
+					t.WebURL = cell.Value
+				case "SPSiteUrl":
+					t.SiteURL = cell.Value
+				}
+			}
+
+			key := strings.ToLower(t.WebURL + "|" + t.ListID)
+			if t.ListID == "" || t.WebURL == "" || found[key] {
+				continue
+			}
+			// Path prefix matches sites with longer URLs and patterns are not supported by the query
+			if len(spec.Sites) > 0 && !matchSites(spec.Sites, t.SiteURL) {
+				continue
+			}
+			found[key] = true
+			targets = append(targets, t)
+		}
+
+		if len(rows) < rowLimit {
+			break
+		}
+		startRow += rowLimit
+	}
+
+	return targets, nil
+}
+
+// searchQueryText returns KQL query of list items of a content type
+// Sites are matched by their static prefixes, the results are checked against the exact sites after
+func searchQueryText(ctID string, sites []string) string {
+	queryText := fmt.Sprintf("ContentTypeId:%s* AND contentclass:STS_ListItem*", ctID)
+	if len(sites) > 0 {
+		paths := make([]string, len(sites))
+		for i, site := range sites {
+			paths[i] = fmt.Sprintf("Path:\"%s*\"", strings.TrimSuffix(util.StaticPrefix(site), "/"))
+		}
+		queryText += " AND (" + strings.Join(paths, " OR ") + ")"
+	}
+	return queryText
+}
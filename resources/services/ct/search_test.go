@@ -0,0 +1,73 @@
+package ct
+
+import "testing"
+
+func TestSearchQueryText(t *testing.T) {
+	cases := []struct {
+		sites []string
+		want  string
+	}{
+		{nil, "ContentTypeId:0x0108* AND contentclass:STS_ListItem*"},
+		{
+			[]string{"https://contoso.sharepoint.com/sites/hr/"},
+			`ContentTypeId:0x0108* AND contentclass:STS_ListItem* AND (Path:"https://contoso.sharepoint.com/sites/hr*")`,
+		},
+		{
+			[]string{"https://contoso.sharepoint.com/sites/proj-*", "https://contoso.sharepoint.com/teams/hr"},
+			`ContentTypeId:0x0108* AND contentclass:STS_ListItem* AND (Path:"https://contoso.sharepoint.com/sites*" OR Path:"https://contoso.sharepoint.com/teams/hr*")`,
+		},
+	}
+
+	for _, c := range cases {
+		if got := searchQueryText("0x0108", c.sites); got != c.want {
+			t.Errorf("searchQueryText(%q) = %q, want %q", c.sites, got, c.want)
+		}
+	}
+}
+
+func TestMatchSite(t *testing.T) {
+	cases := []struct {
+		pattern string
+		siteURL string
+		want    bool
+	}{
+		{"https://contoso.sharepoint.com/sites/hr", "https://contoso.sharepoint.com/sites/hr", true},
+		{"https://contoso.sharepoint.com/sites/hr/", "https://contoso.sharepoint.com/sites/HR", true},
+		{"https://contoso.sharepoint.com/sites/hr", "https://contoso.sharepoint.com/sites/hr-archive", false},
+		{"https://contoso.sharepoint.com/sites/proj-*", "https://contoso.sharepoint.com/sites/proj-a", true},
+		{"https://contoso.sharepoint.com/sites/proj-*", "https://contoso.sharepoint.com/sites/proj-a/sub", false},
+		{"https://contoso.sharepoint.com/sites/proj-*", "https://contoso.sharepoint.com/sites/project", false},
+		{"https://contoso.sharepoint.com/sites/proj-?", "https://contoso.sharepoint.com/sites/proj-b/", true},
+		{"https://contoso.sharepoint.com/*/hr", "https://contoso.sharepoint.com/teams/hr", true},
+	}
+
+	for _, c := range cases {
+		if got := matchSite(c.pattern, c.siteURL); got != c.want {
+			t.Errorf("matchSite(%q, %q) = %v, want %v", c.pattern, c.siteURL, got, c.want)
+		}
+	}
+}
+
+func TestMatchSites(t *testing.T) {
+	sites := []string{"https://contoso.sharepoint.com/sites/proj-*", "https://contoso.sharepoint.com/teams/hr"}
+
+	cases := []struct {
+		siteURL string
+		want    bool
+	}{
+		{"https://contoso.sharepoint.com/sites/proj-a", true},
+		{"https://contoso.sharepoint.com/teams/hr", true},
+		{"https://contoso.sharepoint.com/teams/hr2", false},
+		{"https://contoso.sharepoint.com/sites/hr", false},
+	}
+
+	for _, c := range cases {
+		if got := matchSites(sites, c.siteURL); got != c.want {
+			t.Errorf("matchSites(%q) = %v, want %v", c.siteURL, got, c.want)
+		}
+	}
+
+	if matchSites(nil, "https://contoso.sharepoint.com/sites/hr") {
+		t.Errorf("matchSites(nil) = true, want false")
+	}
+}
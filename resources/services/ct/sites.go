@@ -0,0 +1,65 @@
+package ct
+
+import (
+	"path"
+	"strings"
+
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+)
+
+// Item prop injected into cross-site rollup items
+const siteURLProp = "@SiteUrl"
+
+// getSiteURLs resolves spec sites to site collections absolute URLs
+// Wildcard patterns are expanded with Search API
+func (c *ContentTypesRollup) getSiteURLs(spec Spec) ([]string, error) {
+	var siteURLs []string
+	seen := map[string]bool{}
+	add := func(siteURL string) {
+		siteURL = strings.TrimSuffix(siteURL, "/")
+		if key := strings.ToLower(siteURL); !seen[key] {
+			seen[key] = true
+			siteURLs = append(siteURLs, siteURL)
+		}
+	}
+
+	for _, site := range spec.Sites {
+		if !util.IsPattern(site) {
+			add(site)
+			continue
+		}
+
+		found, err := util.GetSiteCollections(c.sp, util.StaticPrefix(site))
+		if err != nil {
+			return nil, err
+		}
+		for _, siteURL := range found {
+			if matchSite(site, siteURL) {
+				add(siteURL)
+			}
+		}
+	}
+
+	return siteURLs, nil
+}
+
+// matchSite checks if a site collection URL matches a site URL or a wildcard pattern
+func matchSite(pattern string, siteURL string) bool {
+	pattern = strings.ToLower(strings.TrimSuffix(pattern, "/"))
+	siteURL = strings.ToLower(strings.TrimSuffix(siteURL, "/"))
+	if !util.IsPattern(pattern) {
+		return pattern == siteURL
+	}
+	ok, _ := path.Match(pattern, siteURL)
+	return ok
+}
+
+// matchSites checks if a site collection URL matches any of spec sites
+func matchSites(sites []string, siteURL string) bool {
+	for _, site := range sites {
+		if matchSite(site, siteURL) {
+			return true
+		}
+	}
+	return false
+}
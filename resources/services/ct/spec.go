@@ -2,12 +2,20 @@ package ct
 
 import (
 	"fmt"
+	"path"
 	"strings"
 
+	"github.com/koltyakov/cq-source-sharepoint/internal/lookups"
 	"github.com/koltyakov/cq-source-sharepoint/internal/util"
 	"github.com/thoas/go-funk"
 )
 
+// Lists discovery modes
+const (
+	discoveryWebs   = "webs"
+	discoverySearch = "search"
+)
+
 // Spec is the configuration for a list source
 type Spec struct {
 	// REST `$select` OData modificator, fields entity properties array
@@ -19,12 +27,49 @@ type Spec struct {
 	// Optional, and in most of the cases we recommend to avoid it and
 	// prefer to map nested entities to the separate tables
 	Expand []string `json:"expand"`
+	// REST `$filter` OData modificator, a filter string applied to every list of the rollup
+	// Don't use filters on not indexed columns for large lists, such filtering will throttle
+	Filter string `json:"filter"`
+	// Optional, whether to apply content type predicate in list items queries instead of in-memory filtering
+	// Applied only for lists under the list view threshold or with indexed `ContentTypeId` column,
+	// falls back to in-memory filtering when the query is throttled
+	ServerFilter bool `json:"server_filter"`
 	// Optional, an alias for the table name
 	// Don't map different lists to the same table - such scenario is not supported
 	Alias string `json:"alias"`
+	// Optional, resolves selected lookup and user fields IDs to display values
+	// User fields get `<field>_email`, `<field>_login` and `<field>_title` companion columns,
+	// lookup fields get `<field>_value` column with the lookup field value
+	ResolveLookups bool `json:"resolve_lookups"`
+	// Optional, lists discovery mode: `webs` or `search`
+	// `webs` scans lists of the context site (or the `sites` site collections) and all their subwebs
+	// `search` finds lists with items of the content type using Search API across the tenant
+	// or the `sites` site collections, requires a user context auth strategy
+	// If not provided, `webs` will be used
+	Discovery string `json:"discovery"`
+	// Optional, site collections absolute URLs or wildcard patterns to roll up across,
+	// e.g. `https://contoso.sharepoint.com/sites/project-*`, patterns are resolved with Search API
+	// Rollup table gets `site_url` column
+	Sites []string `json:"sites"`
+	// Optional, absolute URL of a site the content type is resolved from,
+	// e.g. the content type hub, the hub content type ID prefix is used to match items in all sites
+	// If not provided, the context site will be used
+	Hub string `json:"hub"`
+	// Optional, enables incremental sync based on items `Modified` watermarks
+	// Watermarks are persisted in CloudQuery state backend per list,
+	// newly discovered lists are synced in full
+	Incremental bool `json:"incremental"`
+	// Optional, an overlap window in minutes subtracted from watermarks to tolerate clock skew
+	// If not provided, 5 minutes will be used, 0 disables the overlap
+	IncrementalOverlap *int `json:"incremental_overlap"`
+	// Optional, a number of webs scanned and lists synced concurrently
+	// If not provided, 1 will be used
+	Concurrency int `json:"concurrency"`
 
 	// Custom fields mapping settings
 	fieldsMapping map[string]string
+	// Selected lookup and user fields resolved to display values
+	lookupFields []*lookups.Field
 }
 
 // SetDefault sets default values for list spec
@@ -58,10 +103,48 @@ func (s *Spec) SetDefault() {
 
 	s.Expand = util.ConcatSlice(s.Expand, defaultExpand)
 	s.Select = util.ConcatSlice(prepProps, util.ConcatSlice(s.Select, apndProps))
+
+	if s.IncrementalOverlap == nil {
+		overlap := 5
+		s.IncrementalOverlap = &overlap
+	}
+
+	if s.Concurrency == 0 {
+		s.Concurrency = 1
+	}
+
+	if s.Discovery == "" {
+		s.Discovery = discoveryWebs
+	}
 }
 
 // Validate validates list spec
 func (s *Spec) Validate() error {
+	if s.Concurrency < 0 {
+		return fmt.Errorf("concurrency can't be negative")
+	}
+
+	if s.IncrementalOverlap != nil && *s.IncrementalOverlap < 0 {
+		return fmt.Errorf("incremental_overlap can't be negative")
+	}
+
+	if s.Discovery != discoveryWebs && s.Discovery != discoverySearch {
+		return fmt.Errorf("discovery should be either %s or %s", discoveryWebs, discoverySearch)
+	}
+
+	for _, site := range s.Sites {
+		if !util.IsAbsoluteURL(site) {
+			return fmt.Errorf("site \"%s\" should be an absolute URL", site)
+		}
+		if _, err := path.Match(site, ""); err != nil {
+			return fmt.Errorf("invalid site pattern \"%s\": %w", site, err)
+		}
+	}
+
+	if s.Hub != "" && !util.IsAbsoluteURL(s.Hub) {
+		return fmt.Errorf("hub \"%s\" should be an absolute URL", s.Hub)
+	}
+
 	aliases := make([]string, len(s.Select))
 	for i, field := range s.Select {
 		aliases[i] = util.NormalizeEntityName(field)
@@ -84,3 +167,21 @@ func (s *Spec) Validate() error {
 func (*Spec) GetAlias(ctName string) string {
 	return strings.ToLower("rollup_" + util.NormalizeEntityName(ctName))
 }
+
+// UsesSearch checks if the rollup depends on Search API
+func (s *Spec) UsesSearch() bool {
+	if s.Discovery == discoverySearch {
+		return true
+	}
+	for _, site := range s.Sites {
+		if util.IsPattern(site) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCrossSite checks if the rollup spans multiple site collections
+func (s *Spec) isCrossSite() bool {
+	return len(s.Sites) > 0 || s.Discovery == discoverySearch
+}
@@ -0,0 +1,139 @@
+package drift
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/rs/zerolog"
+)
+
+// Schema drift policies
+const (
+	// PolicyFail fails client initialization on missing fields and the sync on new fields and type changes
+	PolicyFail = "fail"
+	// PolicyWarn logs and records drift, tables are built as configured
+	PolicyWarn = "warn"
+	// PolicyAdapt drops missing fields from tables, other drift is logged and recorded
+	PolicyAdapt = "adapt"
+)
+
+// Drift kinds
+const (
+	KindMissingField = "missing_field"
+	KindNewField     = "new_field"
+	KindTypeChanged  = "type_changed"
+)
+
+// Finding is a difference between configured and live list schema
+type Finding struct {
+	TableName    string
+	Source       string
+	Kind         string
+	Prop         string
+	FieldType    string
+	PreviousType string
+	Details      string
+	SyncTime     time.Time
+}
+
+// Report collects schema drift findings of tables built by the client
+type Report struct {
+	Policy string
+	logger zerolog.Logger
+
+	mu       sync.Mutex
+	findings []*Finding
+	schemas  map[string]*tableSchema // table name -> live schema
+}
+
+// tableSchema is a live schema of a table source list
+type tableSchema struct {
+	source     string
+	fieldTypes map[string]string // prop -> field type
+	selected   map[string]bool
+}
+
+func NewReport(policy string, logger zerolog.Logger) *Report {
+	return &Report{
+		Policy:  policy,
+		logger:  logger,
+		schemas: map[string]*tableSchema{},
+	}
+}
+
+// Add records a finding
+func (r *Report) Add(f *Finding) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.logger.Warn().
+		Str("table", f.TableName).
+		Str("source", f.Source).
+		Str("kind", f.Kind).
+		Str("prop", f.Prop).
+		Str("field_type", f.FieldType).
+		Msg("schema drift detected")
+
+	r.findings = append(r.findings, f)
+}
+
+// SetSchema records live fields types of a table source list and the props selected to the table
+// Schemas are compared with the ones persisted by the previous sync to detect new fields and type changes
+func (r *Report) SetSchema(tableName string, source string, fieldTypes map[string]string, selected []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ts := &tableSchema{
+		source:     source,
+		fieldTypes: fieldTypes,
+		selected:   map[string]bool{},
+	}
+	for _, prop := range selected {
+		ts.selected[prop] = true
+	}
+	r.schemas[tableName] = ts
+}
+
+// Err returns an error with findings when drift is detected with the fail policy
+// Used at client initialization, when only missing fields are known
+func (r *Report) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.Policy != PolicyFail || len(r.findings) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("schema drift detected: %s", formatFindings(r.findings))
+}
+
+func formatFindings(findings []*Finding) string {
+	items := make([]string, len(findings))
+	for i, f := range findings {
+		items[i] = fmt.Sprintf("%s %s \"%s\"", f.TableName, f.Kind, f.Prop)
+	}
+	return strings.Join(items, ", ")
+}
+
+func (r *Report) GetDestTable() *schema.Table {
+	return &schema.Table{
+		Name:          "sharepoint_schema_drift",
+		Description:   "Schema drift between configured and live lists schema",
+		IsIncremental: true,
+		Columns: []schema.Column{
+			{Name: "sync_time", Type: arrow.FixedWidthTypes.Timestamp_us, PrimaryKey: true, Resolver: schema.PathResolver("SyncTime")},
+			{Name: "table_name", Type: arrow.BinaryTypes.String, PrimaryKey: true, Resolver: schema.PathResolver("TableName")},
+			{Name: "kind", Type: arrow.BinaryTypes.String, PrimaryKey: true, Resolver: schema.PathResolver("Kind")},
+			{Name: "prop", Type: arrow.BinaryTypes.String, PrimaryKey: true, Resolver: schema.PathResolver("Prop")},
+			{Name: "source", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Source")},
+			{Name: "field_type", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("FieldType")},
+			{Name: "previous_type", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("PreviousType")},
+			{Name: "details", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Details")},
+		},
+		Resolver: r.Resolver,
+	}
+}
@@ -0,0 +1,130 @@
+package drift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/cloudquery/plugin-sdk/v4/state"
+)
+
+// Check compares live schemas with the ones persisted by the previous sync
+// and records new fields and type changes, it's called before any table is synced
+// as the state backend is not available at client initialization
+// With the fail policy, an error is returned and schemas are not persisted,
+// so the sync fails until the drift is accepted by a sync with another policy
+func (r *Report) Check(ctx context.Context, st state.Client) error {
+	r.mu.Lock()
+	tableNames := make([]string, 0, len(r.schemas))
+	for tableName := range r.schemas {
+		tableNames = append(tableNames, tableName)
+	}
+	schemas := r.schemas
+	r.mu.Unlock()
+
+	sort.Strings(tableNames)
+
+	var found []*Finding
+	states := map[string]string{}
+	for _, tableName := range tableNames {
+		ts := schemas[tableName]
+		key := "schema_drift_" + tableName
+
+		prev, err := st.GetKey(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to get schema state: %w", err)
+		}
+
+		// The first sync is a baseline
+		if prev != "" {
+			var prevTypes map[string]string
+			if err := json.Unmarshal([]byte(prev), &prevTypes); err != nil {
+				return fmt.Errorf("failed to unmarshal schema state: %w", err)
+			}
+			found = append(found, diffSchema(tableName, ts, prevTypes)...)
+		}
+
+		data, err := json.Marshal(ts.fieldTypes)
+		if err != nil {
+			return err
+		}
+		states[key] = string(data)
+	}
+
+	for _, f := range found {
+		r.Add(f)
+	}
+
+	if r.Policy == PolicyFail && len(found) > 0 {
+		return fmt.Errorf("schema drift detected: %s", formatFindings(found))
+	}
+
+	for key, value := range states {
+		if err := st.SetKey(ctx, key, value); err != nil {
+			return fmt.Errorf("failed to set schema state: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// diffSchema returns fields added since the previous sync which are not selected
+// and selected fields which types are changed
+func diffSchema(tableName string, ts *tableSchema, prevTypes map[string]string) []*Finding {
+	props := make([]string, 0, len(ts.fieldTypes))
+	for prop := range ts.fieldTypes {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+
+	var found []*Finding
+	for _, prop := range props {
+		fieldType := ts.fieldTypes[prop]
+		prevType, ok := prevTypes[prop]
+		switch {
+		case !ok && !ts.selected[prop]:
+			found = append(found, &Finding{
+				TableName: tableName,
+				Source:    ts.source,
+				Kind:      KindNewField,
+				Prop:      prop,
+				FieldType: fieldType,
+				Details:   "list field is added since the previous sync and is not selected",
+			})
+		case ok && prevType != fieldType && ts.selected[prop]:
+			found = append(found, &Finding{
+				TableName:    tableName,
+				Source:       ts.source,
+				Kind:         KindTypeChanged,
+				Prop:         prop,
+				FieldType:    fieldType,
+				PreviousType: prevType,
+				Details:      fmt.Sprintf("field type changed from %s to %s", prevType, fieldType),
+			})
+		}
+	}
+
+	return found
+}
+
+func (r *Report) Resolver(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error {
+	r.mu.Lock()
+	findings := append([]*Finding{}, r.findings...)
+	r.mu.Unlock()
+
+	syncTime := time.Now().UTC()
+	for _, f := range findings {
+		row := *f
+		row.SyncTime = syncTime
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case res <- &row:
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package drift
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffSchema(t *testing.T) {
+	ts := &tableSchema{
+		source: "Lists/Projects",
+		fieldTypes: map[string]string{
+			"Title":     "Text",
+			"Budget":    "Currency",
+			"OwnerId":   "User",
+			"Stage":     "Choice",
+			"Estimate":  "Number",
+			"ManagerId": "User",
+		},
+		selected: map[string]bool{"Title": true, "Budget": true, "OwnerId": true, "Estimate": true},
+	}
+
+	prevTypes := map[string]string{
+		"Title":     "Text",
+		"Budget":    "Number",
+		"OwnerId":   "User",
+		"ManagerId": "Lookup",
+	}
+
+	var got []string
+	for _, f := range diffSchema("sharepoint_projects", ts, prevTypes) {
+		got = append(got, f.Kind+" "+f.Prop+" "+f.PreviousType+" "+f.FieldType)
+	}
+
+	// New selected fields and type changes of not selected fields are not reported
+	want := []string{
+		"type_changed Budget Number Currency",
+		"new_field Stage  Choice",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffSchema() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,146 @@
+package lists
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+	"github.com/koltyakov/gosip/api"
+)
+
+// getAttachmentsTable returns a child table with list items attachments
+func (l *Lists) getAttachmentsTable(listURI string, spec Spec, listTable *schema.Table) *schema.Table {
+	table := &schema.Table{
+		Name:        listTable.Name + "_attachments",
+		Description: "Attachments of " + listURI + " items",
+		Columns: []schema.Column{
+			{Name: "item_id", Type: arrow.PrimitiveTypes.Int32, Description: "ItemId", PrimaryKey: true, Resolver: propResolver("ItemId")},
+			{Name: "file_name", Type: arrow.BinaryTypes.String, Description: "FileName", PrimaryKey: true, Resolver: propResolver("FileName")},
+			{Name: "server_relative_url", Type: arrow.BinaryTypes.String, Description: "ServerRelativeUrl", Resolver: propResolver("ServerRelativeUrl")},
+			{Name: "size", Type: arrow.PrimitiveTypes.Int64, Description: "Length", Resolver: propResolver("Length")},
+		},
+	}
+
+	if spec.Attachments.Content {
+		table.Columns = append(table.Columns, schema.Column{
+			Name: "content", Type: arrow.BinaryTypes.Binary, Description: "Content", Resolver: propResolver("Content"),
+		})
+	}
+
+	if isMultiSite(listURI) {
+		table.Columns = append(table.Columns, targetColumns()...)
+	}
+
+	table.Resolver = l.AttachmentsResolver(listURI, spec)
+
+	return table
+}
+
+func (l *Lists) AttachmentsResolver(listURI string, spec Spec) ResolverClosure {
+	targets := l.targets[listURI]
+
+	return func(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error {
+		item := parent.Item.(map[string]any)
+		if hasAttachments, _ := item["Attachments"].(bool); !hasAttachments {
+			return nil
+		}
+
+		t := getItemTarget(targets, item)
+		itemID := getItemID(item)
+
+		resp, err := l.getList(t).Items().GetByID(itemID).Attachments().Get()
+		if err != nil {
+			return fmt.Errorf("failed to get attachments of item %d: %w", itemID, err)
+		}
+
+		var attachments []map[string]any
+		if err := json.Unmarshal(resp.Normalized(), &attachments); err != nil {
+			return fmt.Errorf("failed to unmarshal attachments: %w", err)
+		}
+
+		web := l.getWeb(t)
+		for _, attachment := range attachments {
+			fileURL, _ := attachment["ServerRelativeUrl"].(string)
+			file := web.GetFile(fileURL)
+
+			size, err := getFileLength(file)
+			if err != nil {
+				return fmt.Errorf("failed to get attachment \"%s\": %w", fileURL, err)
+			}
+
+			attachment["ItemId"] = itemID
+			attachment["Length"] = size
+
+			if spec.Attachments.Content && size <= spec.Attachments.MaxSize {
+				content, err := file.Download()
+				if err != nil {
+					return fmt.Errorf("failed to download attachment \"%s\": %w", fileURL, err)
+				}
+				attachment["Content"] = content
+			}
+		}
+
+		t.setTargetProps(attachments)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case res <- attachments:
+		}
+
+		return nil
+	}
+}
+
+// getWeb returns web object of a target
+func (l *Lists) getWeb(t *listTarget) *api.Web {
+	if t.WebURL == "" {
+		return l.sp.Web()
+	}
+	return util.GetWeb(l.sp, t.WebURL)
+}
+
+// getItemTarget returns list target of a parent item
+func getItemTarget(targets []*listTarget, item map[string]any) *listTarget {
+	if listID, ok := item[listIDProp].(string); ok {
+		for _, t := range targets {
+			if t.ListID == listID {
+				return t
+			}
+		}
+	}
+	return targets[0]
+}
+
+// getItemID returns item ID from list item response
+func getItemID(item map[string]any) int {
+	if id, ok := item["ID"].(float64); ok {
+		return int(id)
+	}
+	return 0
+}
+
+// getFileLength returns file size in bytes
+func getFileLength(file *api.File) (int64, error) {
+	resp, err := file.Select("Length").Get()
+	if err != nil {
+		return 0, err
+	}
+
+	var info map[string]any
+	if err := json.Unmarshal(resp.Normalized(), &info); err != nil {
+		return 0, err
+	}
+
+	return parseInt64(info["Length"])
+}
+
+// propResolver resolves column value by item property path
+func propResolver(prop string) schema.ColumnResolver {
+	return func(ctx context.Context, meta schema.ClientMeta, resource *schema.Resource, c schema.Column) error {
+		return resource.Set(c.Name, util.GetRespValByProp(resource.Item.(map[string]any), prop))
+	}
+}
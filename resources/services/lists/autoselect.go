@@ -0,0 +1,98 @@
+package lists
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+)
+
+// Base type fields which are not treated as system fields
+var userBaseFields = []string{"Title"}
+
+// Fields types which can't be requested with `$select` or are covered by other options
+var notSelectableTypes = []string{"Computed", "Attachments"}
+
+type autoSelectField struct {
+	InternalName       string `json:"InternalName"`
+	EntityPropertyName string `json:"EntityPropertyName"`
+	TypeAsString       string `json:"TypeAsString"`
+	Hidden             bool   `json:"Hidden"`
+	ReadOnlyField      bool   `json:"ReadOnlyField"`
+	FromBaseType       bool   `json:"FromBaseType"`
+}
+
+// getAutoSelectProps discovers list fields props according to auto select settings
+// fieldsData is a normalized JSON array of list fields
+func getAutoSelectProps(spec Spec, fieldsData []byte) ([]string, error) {
+	var fields []*autoSelectField
+	if err := json.Unmarshal(fieldsData, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal fields: %w", err)
+	}
+
+	// Column names already taken by selected props
+	names := map[string]bool{}
+	for _, prop := range spec.Select {
+		names[util.NormalizeEntityName(prop)] = true
+		if alias, ok := spec.fieldsMapping[prop]; ok {
+			names[util.NormalizeEntityName(alias)] = true
+		}
+	}
+
+	var props []string
+	for _, field := range fields {
+		if !spec.AutoSelect.match(field) {
+			continue
+		}
+
+		prop := field.EntityPropertyName
+		if strings.HasPrefix(field.TypeAsString, "Lookup") || strings.HasPrefix(field.TypeAsString, "User") {
+			prop += "Id"
+		}
+
+		name := util.NormalizeEntityName(prop)
+		if prop == "" || names[name] {
+			continue
+		}
+		names[name] = true
+
+		props = append(props, prop)
+	}
+
+	return props, nil
+}
+
+// match checks if a field should be auto selected
+func (s AutoSelectSpec) match(field *autoSelectField) bool {
+	if util.Contains(notSelectableTypes, field.TypeAsString) {
+		return false
+	}
+	if field.Hidden && !s.Hidden {
+		return false
+	}
+	if field.ReadOnlyField && !s.ReadOnly {
+		return false
+	}
+	if field.FromBaseType && !s.System && !util.Contains(userBaseFields, field.InternalName) {
+		return false
+	}
+
+	if len(s.Include) > 0 && !matchFieldPatterns(s.Include, field) {
+		return false
+	}
+	return !matchFieldPatterns(s.Exclude, field)
+}
+
+// matchFieldPatterns checks if field's internal or entity property name matches any of patterns
+func matchFieldPatterns(patterns []string, field *autoSelectField) bool {
+	for _, pattern := range patterns {
+		for _, name := range []string{field.InternalName, field.EntityPropertyName} {
+			if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(name)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
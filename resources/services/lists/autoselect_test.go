@@ -0,0 +1,78 @@
+package lists
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetAutoSelectProps(t *testing.T) {
+	fieldsData := []byte(`[
+		{"InternalName": "Title", "EntityPropertyName": "Title", "TypeAsString": "Text", "FromBaseType": true},
+		{"InternalName": "ContentType", "EntityPropertyName": "ContentType", "TypeAsString": "Computed", "FromBaseType": true},
+		{"InternalName": "Attachments", "EntityPropertyName": "Attachments", "TypeAsString": "Attachments", "FromBaseType": true},
+		{"InternalName": "_UIVersionString", "EntityPropertyName": "OData__UIVersionString", "TypeAsString": "Text", "ReadOnlyField": true, "FromBaseType": true},
+		{"InternalName": "ProjectCode", "EntityPropertyName": "ProjectCode", "TypeAsString": "Text"},
+		{"InternalName": "Project_x0020_Owner", "EntityPropertyName": "ProjectOwner", "TypeAsString": "User"},
+		{"InternalName": "ProjectTags", "EntityPropertyName": "ProjectTags", "TypeAsString": "LookupMulti"},
+		{"InternalName": "Budget", "EntityPropertyName": "Budget", "TypeAsString": "Currency"},
+		{"InternalName": "Score", "EntityPropertyName": "Score", "TypeAsString": "Number", "ReadOnlyField": true},
+		{"InternalName": "SyncKey", "EntityPropertyName": "SyncKey", "TypeAsString": "Text", "Hidden": true}
+	]`)
+
+	cases := []struct {
+		name string
+		spec Spec
+		want []string
+	}{
+		{
+			name: "defaults",
+			spec: Spec{},
+			want: []string{"Title", "ProjectCode", "ProjectOwnerId", "ProjectTagsId", "Budget"},
+		},
+		{
+			name: "include by internal and entity names",
+			spec: Spec{AutoSelect: AutoSelectSpec{Include: []string{"project_x0020_*", "projectc*"}}},
+			want: []string{"ProjectCode", "ProjectOwnerId"},
+		},
+		{
+			name: "include with exclude",
+			spec: Spec{AutoSelect: AutoSelectSpec{Include: []string{"Project*"}, Exclude: []string{"ProjectTags"}}},
+			want: []string{"ProjectCode", "ProjectOwnerId"},
+		},
+		{
+			name: "hidden and read only",
+			spec: Spec{AutoSelect: AutoSelectSpec{Hidden: true, ReadOnly: true}},
+			want: []string{"Title", "ProjectCode", "ProjectOwnerId", "ProjectTagsId", "Budget", "Score", "SyncKey"},
+		},
+		{
+			name: "read only system fields",
+			spec: Spec{AutoSelect: AutoSelectSpec{ReadOnly: true, System: true, Include: []string{"_UI*", "Title"}}},
+			want: []string{"Title", "OData__UIVersionString"},
+		},
+		{
+			name: "system fields require read only for read only fields",
+			spec: Spec{AutoSelect: AutoSelectSpec{System: true, Include: []string{"_UI*"}}},
+			want: nil,
+		},
+		{
+			name: "already selected props",
+			spec: Spec{Select: []string{"ID", "Title", "ProjectOwnerId"}, AutoSelect: AutoSelectSpec{Include: []string{"Title", "Project*"}}},
+			want: []string{"ProjectCode", "ProjectTagsId"},
+		},
+		{
+			name: "aliased selected props",
+			spec: Spec{Select: []string{"ProjectCode"}, fieldsMapping: map[string]string{"ProjectCode": "Budget"}},
+			want: []string{"Title", "ProjectOwnerId", "ProjectTagsId"},
+		},
+	}
+
+	for _, c := range cases {
+		got, err := getAutoSelectProps(c.spec, fieldsData)
+		if err != nil {
+			t.Fatalf("%s: getAutoSelectProps() error = %v", c.name, err)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: getAutoSelectProps() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
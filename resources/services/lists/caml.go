@@ -0,0 +1,269 @@
+package lists
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/koltyakov/cq-source-sharepoint/internal/lookups"
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+	"github.com/koltyakov/gosip/api"
+)
+
+// camlField is a list field referenced by a selected prop
+type camlField struct {
+	Name   string // Field internal name used in CAML and rendered rows
+	Type   string // Field type, e.g. `Boolean` or `Number`
+	Lookup bool   // Lookup or user field selected as `FieldNameId` or `FieldName/Prop`
+	Multi  bool   // Allows multiple values
+}
+
+// getCamlFields maps selected props to list fields
+func getCamlFields(props []string, fieldsData []api.FieldResp) map[string]*camlField {
+	fields := map[string]*camlField{}
+	for _, prop := range props {
+		base, _, nested := strings.Cut(prop, "/")
+		for _, fieldResp := range fieldsData {
+			fieldData := fieldResp.Data()
+			isLookup := strings.HasPrefix(fieldData.TypeAsString, "Lookup") || strings.HasPrefix(fieldData.TypeAsString, "User")
+			match := fieldData.EntityPropertyName == base
+			if isLookup && !nested {
+				match = fieldData.EntityPropertyName+"Id" == prop
+			}
+			if match {
+				fields[prop] = &camlField{
+					Name:   fieldData.InternalName,
+					Type:   fieldData.TypeAsString,
+					Lookup: isLookup,
+					Multi:  strings.HasSuffix(fieldData.TypeAsString, "Multi"),
+				}
+				break
+			}
+		}
+	}
+	return fields
+}
+
+// syncCAML pages through list items using RenderListDataAsStream and CAML view XML
+func (l *Lists) syncCAML(ctx context.Context, t *listTarget, spec Spec, lr *lookups.Resolver, res chan<- any) error {
+	top := 5000
+	if spec.Top > 0 && spec.Top < 5000 {
+		top = spec.Top
+	}
+
+	params := &api.RenderListDataParameters{
+		ViewXML:       getViewXML(spec, top),
+		RenderOptions: 2, // ListData
+		DatesInUtc:    true,
+	}
+	if spec.Folder != "" {
+		params.FolderServerRelativeURL = spec.Folder
+		if !strings.HasPrefix(spec.Folder, "/") {
+			params.FolderServerRelativeURL = l.toServerRelativeURL(t.ListURI) + "/" + spec.Folder
+		}
+	}
+
+	list := l.getList(t)
+	for {
+		resp, err := list.RenderListDataAsStream(params, nil)
+		if err != nil {
+			return fmt.Errorf("failed to render list data: %w", err)
+		}
+
+		var data struct {
+			Row      []map[string]any `json:"Row"`
+			NextHref string           `json:"NextHref"`
+		}
+		if err := json.Unmarshal(resp, &data); err != nil {
+			return fmt.Errorf("failed to unmarshal list data: %w", err)
+		}
+
+		itemList := make([]map[string]any, len(data.Row))
+		for i, row := range data.Row {
+			itemList[i] = camlRowToItem(row, spec)
+		}
+
+		if err := lr.Resolve(t.WebURL, t.ListID, itemList); err != nil {
+			return err
+		}
+
+		t.setTargetProps(itemList)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case res <- itemList:
+		}
+
+		if data.NextHref == "" {
+			break
+		}
+		params.Paging = strings.TrimPrefix(data.NextHref, "?")
+	}
+
+	return nil
+}
+
+// getViewXML returns CAML view XML
+// When only a query is provided, it's wrapped into a recursive view with selected fields
+func getViewXML(spec Spec, top int) string {
+	if strings.Contains(spec.CAML, "<View") {
+		return spec.CAML
+	}
+
+	viewFields := ""
+	added := map[string]bool{}
+	for _, prop := range spec.Select {
+		name := prop
+		if f, ok := spec.camlFields[prop]; ok {
+			name = f.Name
+		}
+		if added[name] || strings.Contains(name, "/") {
+			continue
+		}
+		added[name] = true
+		viewFields += fmt.Sprintf(`<FieldRef Name="%s" />`, name)
+	}
+
+	return fmt.Sprintf(
+		`<View Scope="RecursiveAll"><Query>%s</Query><ViewFields>%s</ViewFields><RowLimit Paged="TRUE">%d</RowLimit></View>`,
+		spec.CAML, viewFields, top,
+	)
+}
+
+// camlRowToItem maps a rendered row onto the list item shape used by table columns
+// Rendered values are formatted for display, raw values are taken from `FieldName.` props when available
+func camlRowToItem(row map[string]any, spec Spec) map[string]any {
+	item := map[string]any{}
+	for _, prop := range spec.Select {
+		field, ok := spec.camlFields[prop]
+		if !ok {
+			field = &camlField{Name: prop}
+		}
+
+		if !field.Lookup {
+			v, ok := row[field.Name+"."]
+			if !ok {
+				v = row[field.Name]
+			}
+			switch field.Type {
+			case "Boolean", "Attachments":
+				v = camlBool(v)
+			case "Number", "Integer", "Counter", "Currency":
+				v = camlNumber(v)
+			}
+			item[prop] = v
+			continue
+		}
+
+		lookups, _ := row[field.Name].([]any)
+		base, sub, nested := strings.Cut(prop, "/")
+		if !nested {
+			sub = "Id"
+		}
+
+		values := make([]any, 0, len(lookups))
+		for _, lookup := range lookups {
+			values = append(values, lookupValue(lookup, sub))
+		}
+
+		var value any = values
+		if !field.Multi {
+			value = nil
+			if len(values) > 0 {
+				value = values[0]
+			}
+		}
+
+		if !nested {
+			item[prop] = value
+			continue
+		}
+
+		m, ok := item[base].(map[string]any)
+		if !ok {
+			m = map[string]any{}
+			item[base] = m
+		}
+		m[sub] = value
+	}
+
+	// Rendered rows contain numbers and flags as strings, child tables expect REST API value types
+	if _, ok := spec.camlFields["ID"]; !ok {
+		item["ID"] = camlNumber(item["ID"])
+	}
+	if _, ok := spec.camlFields["Attachments"]; !ok && item["Attachments"] != nil {
+		item["Attachments"] = camlBool(item["Attachments"])
+	}
+
+	return item
+}
+
+// camlBool converts a rendered flag value, e.g. `1`, `Yes` or `True`, to bool
+func camlBool(value any) any {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+	switch strings.ToLower(s) {
+	case "1", "yes", "true":
+		return true
+	default:
+		return false
+	}
+}
+
+// camlNumber converts a raw number value to float64
+// Rendered values can be localized (e.g. `1,234.5`), such values are cleared of group separators
+// Values which still can't be parsed are skipped rather than failing the sync
+func camlNumber(value any) any {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+	if s == "" {
+		return nil
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return n
+	}
+	s = strings.NewReplacer(",", "", " ", "", "\u00a0", "", "\u202f", "").Replace(s)
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return n
+	}
+	return nil
+}
+
+// lookupValue returns a prop of a rendered lookup or user value
+// Lookups are rendered as `{ lookupId, lookupValue }`, users as `{ id, title, email, ... }`
+func lookupValue(lookup any, prop string) any {
+	m, ok := lookup.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	switch strings.ToLower(prop) {
+	case "id":
+		if v, ok := m["lookupId"]; ok {
+			return v
+		}
+		return m["id"]
+	case "title":
+		if v, ok := m["lookupValue"]; ok {
+			return v
+		}
+		return m["title"]
+	}
+
+	return util.GetRespValByProp(lowerKeys(m), strings.ToLower(prop))
+}
+
+func lowerKeys(m map[string]any) map[string]any {
+	lm := make(map[string]any, len(m))
+	for k, v := range m {
+		lm[strings.ToLower(k)] = v
+	}
+	return lm
+}
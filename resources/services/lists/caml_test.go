@@ -0,0 +1,121 @@
+package lists
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCamlRowToItem(t *testing.T) {
+	cases := []struct {
+		name string
+		spec Spec
+		row  map[string]any
+		want map[string]any
+	}{
+		{
+			name: "lookups and flags",
+			spec: Spec{
+				Select: []string{"ID", "Title", "Attachments", "AssignedToId", "AssignedTo/Title"},
+				camlFields: map[string]*camlField{
+					"Title":            {Name: "Title", Type: "Text"},
+					"Attachments":      {Name: "Attachments", Type: "Attachments"},
+					"AssignedToId":     {Name: "AssignedTo", Type: "User", Lookup: true},
+					"AssignedTo/Title": {Name: "AssignedTo", Type: "User", Lookup: true},
+				},
+			},
+			row: map[string]any{
+				"ID":          "42",
+				"Title":       "Task",
+				"Attachments": "1",
+				"AssignedTo": []any{
+					map[string]any{"id": "7", "title": "Jane Doe", "email": "jane@contoso.com"},
+				},
+			},
+			want: map[string]any{
+				"ID":           float64(42),
+				"Title":        "Task",
+				"Attachments":  true,
+				"AssignedToId": "7",
+				"AssignedTo":   map[string]any{"Title": "Jane Doe"},
+			},
+		},
+		{
+			name: "booleans and numbers",
+			spec: Spec{
+				Select: []string{"ID", "Approved", "Archived", "Budget", "Score"},
+				camlFields: map[string]*camlField{
+					"ID":       {Name: "ID", Type: "Counter"},
+					"Approved": {Name: "Approved", Type: "Boolean"},
+					"Archived": {Name: "Archived", Type: "Boolean"},
+					"Budget":   {Name: "Budget", Type: "Number"},
+					"Score":    {Name: "Score", Type: "Integer"},
+				},
+			},
+			row: map[string]any{
+				"ID":       "1,042",
+				"Approved": "Yes",
+				"Archived": "No",
+				"Budget":   "12,345.5",
+				"Budget.":  "12345.5",
+				"Score":    "1,200",
+			},
+			want: map[string]any{
+				"ID":       float64(1042),
+				"Approved": true,
+				"Archived": false,
+				"Budget":   12345.5,
+				"Score":    float64(1200),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		if got := camlRowToItem(c.row, c.spec); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: camlRowToItem() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestCamlBool(t *testing.T) {
+	cases := []struct {
+		value any
+		want  any
+	}{
+		{"1", true},
+		{"Yes", true},
+		{"True", true},
+		{"0", false},
+		{"No", false},
+		{"", false},
+		{true, true},
+		{nil, nil},
+	}
+
+	for _, c := range cases {
+		if got := camlBool(c.value); got != c.want {
+			t.Errorf("camlBool(%v) = %v, want %v", c.value, got, c.want)
+		}
+	}
+}
+
+func TestCamlNumber(t *testing.T) {
+	cases := []struct {
+		value any
+		want  any
+	}{
+		{"42", float64(42)},
+		{"12345.5", 12345.5},
+		{"1,234", float64(1234)},
+		{"1\u00a0234", float64(1234)},
+		{"", nil},
+		{"n/a", nil},
+		{float64(7), float64(7)},
+		{nil, nil},
+	}
+
+	for _, c := range cases {
+		if got := camlNumber(c.value); got != c.want {
+			t.Errorf("camlNumber(%v) = %v, want %v", c.value, got, c.want)
+		}
+	}
+}
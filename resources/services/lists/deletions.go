@@ -0,0 +1,104 @@
+package lists
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/cloudquery/plugin-sdk/v4/types"
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+	"github.com/koltyakov/gosip/api"
+)
+
+// SharePoint change type for deleted objects (SP.ChangeType.DeleteObject)
+const changeTypeDeleteObject = 3
+
+// GetDeletionsTable returns a companion table with items deleted from the list
+// Deletions are read from the list change log since the change token persisted in the state
+func (l *Lists) GetDeletionsTable(listURI string, listTable *schema.Table) *schema.Table {
+	table := &schema.Table{
+		Name:          listTable.Name + "_deletions",
+		Description:   "Items deleted from " + listURI,
+		IsIncremental: true,
+		Columns: []schema.Column{
+			{Name: "list_id", Type: types.UUID, Description: "ListId", PrimaryKey: true, Resolver: schema.PathResolver("ListID")},
+			{Name: "item_id", Type: arrow.PrimitiveTypes.Int32, Description: "ItemId", PrimaryKey: true, Resolver: schema.PathResolver("ItemID")},
+			{Name: "unique_id", Type: types.UUID, Description: "UniqueId", Resolver: schema.PathResolver("UniqueID")},
+			{Name: "change_time", Type: arrow.FixedWidthTypes.Timestamp_us, Description: "Time", Resolver: schema.PathResolver("Time")},
+		},
+	}
+
+	table.Resolver = l.DeletionsResolver(listURI, table)
+
+	return table
+}
+
+func (l *Lists) DeletionsResolver(listURI string, table *schema.Table) ResolverClosure {
+	targets := l.targets[listURI]
+
+	return func(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error {
+		for _, t := range targets {
+			if err := l.syncDeletions(ctx, meta, t, table, res); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func (l *Lists) syncDeletions(ctx context.Context, meta schema.ClientMeta, t *listTarget, table *schema.Table, res chan<- any) error {
+	stateClient := util.GetState(meta)
+	stateKey := t.stateKey(table.Name)
+
+	token, err := stateClient.GetKey(ctx, stateKey)
+	if err != nil {
+		return fmt.Errorf("failed to get state key \"%s\": %w", stateKey, err)
+	}
+
+	l.logger.Debug().Str("table", table.Name).Str("list", t.ListURI).Str("token", token).Msg("reading list changes")
+
+	fetchLimit := 1000
+	changes, err := l.getList(t).Changes().GetChanges(&api.ChangeQuery{
+		ChangeTokenStart: token,
+		Item:             true,
+		DeleteObject:     true,
+		FetchLimit:       fetchLimit,
+	})
+
+	for {
+		if err != nil {
+			return fmt.Errorf("failed to get changes: %w", err)
+		}
+
+		data := changes.Data()
+		for _, change := range data {
+			if change.ChangeToken != nil {
+				token = change.ChangeToken.StringValue
+			}
+			if change.ChangeType != changeTypeDeleteObject {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case res <- change:
+			}
+		}
+
+		if len(data) < fetchLimit {
+			break
+		}
+		changes, err = changes.GetNextPage()
+	}
+
+	if token == "" {
+		return nil
+	}
+	if err := stateClient.SetKey(ctx, stateKey, token); err != nil {
+		return fmt.Errorf("failed to set state key \"%s\": %w", stateKey, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package lists
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+	"github.com/koltyakov/cq-source-sharepoint/resources/services/drift"
+)
+
+// Items props which are not backed by list fields
+var itemProps = []string{"Id", "HasUniqueRoleAssignments", "FileSystemObjectType", "ServerRedirectedEmbedUri", "ServerRedirectedEmbedUrl"}
+
+// Expandable item entities which are not backed by list fields
+var itemEntities = []string{"File", "Folder", "ParentList", "ContentType", "FieldValuesAsText", "TaxCatchAll", "AttachmentFiles", "Versions", "RoleAssignments"}
+
+// detectDrift compares selected props with live list fields and records missing fields
+// Live fields types are recorded to detect new fields and type changes against the previous sync
+// With the adapt policy, returns selected props without missing fields
+func (l *Lists) detectDrift(listURI string, tableName string, spec Spec, fieldsData []byte) ([]string, error) {
+	var fields []*autoSelectField
+	if err := json.Unmarshal(fieldsData, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal fields: %w", err)
+	}
+
+	fieldProps := map[string]*autoSelectField{}
+	for _, field := range fields {
+		fieldProps[fieldProp(field)] = field
+		fieldProps[field.EntityPropertyName] = field
+	}
+
+	adapt := l.drift.Policy == drift.PolicyAdapt
+
+	// Fields which would be selected automatically by default are tracked for new fields
+	fieldTypes := map[string]string{}
+	for _, field := range fields {
+		if prop := fieldProp(field); prop != "" && (AutoSelectSpec{}).match(field) {
+			fieldTypes[prop] = field.TypeAsString
+		}
+	}
+
+	selectProps := make([]string, 0, len(spec.Select))
+	for _, prop := range spec.Select {
+		base, _, _ := strings.Cut(prop, "/")
+		if field, ok := fieldProps[base]; ok {
+			if base == prop {
+				fieldTypes[prop] = field.TypeAsString
+			}
+			selectProps = append(selectProps, prop)
+			continue
+		}
+
+		if util.Contains(itemProps, base) || util.Contains(itemEntities, base) {
+			selectProps = append(selectProps, prop)
+			continue
+		}
+
+		l.drift.Add(&drift.Finding{
+			TableName: tableName,
+			Source:    listURI,
+			Kind:      drift.KindMissingField,
+			Prop:      prop,
+			Details:   "selected prop is not found in list fields",
+		})
+		if !adapt {
+			selectProps = append(selectProps, prop)
+		}
+	}
+
+	l.drift.SetSchema(tableName, listURI, fieldTypes, selectProps)
+
+	return selectProps, nil
+}
+
+// fieldProp returns item prop of a field, lookup and user fields are selected with `Id` suffix
+func fieldProp(field *autoSelectField) string {
+	if field.EntityPropertyName == "" {
+		return ""
+	}
+	if strings.HasPrefix(field.TypeAsString, "Lookup") || strings.HasPrefix(field.TypeAsString, "User") {
+		return field.EntityPropertyName + "Id"
+	}
+	return field.EntityPropertyName
+}
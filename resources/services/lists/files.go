@@ -0,0 +1,139 @@
+package lists
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/cloudquery/plugin-sdk/v4/types"
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+	"github.com/koltyakov/gosip/api"
+	"github.com/thoas/go-funk"
+)
+
+// Item props injected into document library items
+const (
+	fileContentProp = "@FileContent"
+	fileHashProp    = "@FileHash"
+)
+
+// fileProps are document library file entity props with their types
+var fileProps = map[string]arrow.DataType{
+	"File/Name":              arrow.BinaryTypes.String,
+	"File/ServerRelativeUrl": arrow.BinaryTypes.String,
+	"File/Length":            arrow.PrimitiveTypes.Int64,
+	"File/TimeCreated":       arrow.FixedWidthTypes.Timestamp_us,
+	"File/TimeLastModified":  arrow.FixedWidthTypes.Timestamp_us,
+	"File/CheckOutType":      arrow.PrimitiveTypes.Int32,
+	"File/MajorVersion":      arrow.PrimitiveTypes.Int32,
+	"File/MinorVersion":      arrow.PrimitiveTypes.Int32,
+	"File/UIVersion":         arrow.PrimitiveTypes.Int32,
+	"File/ETag":              arrow.BinaryTypes.String,
+	"File/UniqueId":          types.UUID,
+}
+
+// defaultFileProps are selected when files option is enabled
+var defaultFileProps = []string{
+	"File/Name",
+	"File/ServerRelativeUrl",
+	"File/Length",
+	"File/TimeLastModified",
+	"File/CheckOutType",
+	"File/MajorVersion",
+	"File/MinorVersion",
+	"File/ETag",
+}
+
+// fileColumns are the columns with file content and hash
+func fileColumns(spec Spec) []schema.Column {
+	cols := []schema.Column{}
+	if spec.Files.Content {
+		cols = append(cols, schema.Column{Name: "file_content", Type: arrow.BinaryTypes.Binary, Description: "File content", Resolver: propResolver(fileContentProp)})
+	}
+	if spec.Files.Hash {
+		cols = append(cols, schema.Column{Name: "file_sha256", Type: arrow.BinaryTypes.String, Description: "File SHA-256 checksum", Resolver: propResolver(fileHashProp)})
+	}
+	return cols
+}
+
+// fetchFiles downloads items files content and/or calculates SHA-256 checksums
+func (l *Lists) fetchFiles(t *listTarget, items []map[string]any, spec Spec) error {
+	if !spec.Files.Content && !spec.Files.Hash {
+		return nil
+	}
+
+	web := l.getWeb(t)
+	for _, item := range items {
+		fileURL, ok := util.GetRespValByProp(item, "File/ServerRelativeUrl").(string)
+		if !ok || fileURL == "" { // Folders have no file
+			continue
+		}
+
+		size, _ := parseInt64(util.GetRespValByProp(item, "File/Length"))
+		if !spec.Files.match(fileURL, size) {
+			continue
+		}
+
+		if err := fetchFile(web.GetFile(fileURL), item, spec); err != nil {
+			return fmt.Errorf("failed to download file \"%s\": %w", fileURL, err)
+		}
+	}
+
+	return nil
+}
+
+func fetchFile(file *api.File, item map[string]any, spec Spec) error {
+	reader, err := file.GetReader()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	var src io.Reader = io.TeeReader(reader, hash)
+
+	if spec.Files.Content {
+		content, err := io.ReadAll(src)
+		if err != nil {
+			return err
+		}
+		item[fileContentProp] = content
+	} else if _, err := io.Copy(io.Discard, src); err != nil {
+		return err
+	}
+
+	if spec.Files.Hash {
+		item[fileHashProp] = hex.EncodeToString(hash.Sum(nil))
+	}
+
+	return nil
+}
+
+// match checks if a file passes size and extension filters
+func (s *FilesSpec) match(fileURL string, size int64) bool {
+	if s.MaxSize > 0 && size > s.MaxSize {
+		return false
+	}
+	if len(s.Extensions) == 0 {
+		return true
+	}
+	ext := strings.TrimPrefix(strings.ToLower(path.Ext(fileURL)), ".")
+	return funk.ContainsString(s.Extensions, ext)
+}
+
+// parseInt64 parses Edm.Int64 values which are serialized as strings
+func parseInt64(value any) (int64, error) {
+	switch v := value.(type) {
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	case float64:
+		return int64(v), nil
+	}
+	return 0, fmt.Errorf("unexpected value type %T", value)
+}
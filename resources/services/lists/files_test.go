@@ -0,0 +1,50 @@
+package lists
+
+import "testing"
+
+func TestFilesSpecMatch(t *testing.T) {
+	cases := []struct {
+		name    string
+		spec    FilesSpec
+		fileURL string
+		size    int64
+		want    bool
+	}{
+		{"no limits", FilesSpec{}, "/sites/hr/docs/a.bin", 1 << 40, true},
+		{"under max size", FilesSpec{MaxSize: 100}, "/sites/hr/docs/a.pdf", 99, true},
+		{"exactly max size", FilesSpec{MaxSize: 100}, "/sites/hr/docs/a.pdf", 100, true},
+		{"over max size", FilesSpec{MaxSize: 100}, "/sites/hr/docs/a.pdf", 101, false},
+		{"allowed extension", FilesSpec{Extensions: []string{"pdf", "docx"}}, "/sites/hr/docs/a.docx", 1, true},
+		{"extension case", FilesSpec{Extensions: []string{"pdf"}}, "/sites/hr/docs/A.PDF", 1, true},
+		{"other extension", FilesSpec{Extensions: []string{"pdf"}}, "/sites/hr/docs/a.xlsx", 1, false},
+		{"no extension", FilesSpec{Extensions: []string{"pdf"}}, "/sites/hr/docs/readme", 1, false},
+		{"dotted folder", FilesSpec{Extensions: []string{"pdf"}}, "/sites/hr/docs/v1.pdf/readme", 1, false},
+		{"allowed extension over max size", FilesSpec{MaxSize: 10, Extensions: []string{"pdf"}}, "/sites/hr/docs/a.pdf", 11, false},
+	}
+
+	for _, c := range cases {
+		if got := c.spec.match(c.fileURL, c.size); got != c.want {
+			t.Errorf("%s: match(%q, %d) = %v, want %v", c.name, c.fileURL, c.size, got, c.want)
+		}
+	}
+}
+
+func TestParseInt64(t *testing.T) {
+	cases := []struct {
+		value   any
+		want    int64
+		wantErr bool
+	}{
+		{"12345678901", 12345678901, false},
+		{float64(42), 42, false},
+		{"n/a", 0, true},
+		{nil, 0, true},
+	}
+
+	for _, c := range cases {
+		got, err := parseInt64(c.value)
+		if (err != nil) != c.wantErr || got != c.want {
+			t.Errorf("parseInt64(%v) = %d, %v, want %d, error %v", c.value, got, err, c.want, c.wantErr)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package lists
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/cloudquery/plugin-sdk/v4/types"
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+)
+
+// inventoryInfo is a list metadata for lists inventory table
+type inventoryInfo struct {
+	listInfo
+	BaseTemplate             int    `json:"BaseTemplate"`
+	BaseType                 int    `json:"BaseType"`
+	ItemCount                int    `json:"ItemCount"`
+	Created                  string `json:"Created"`
+	LastItemModifiedDate     string `json:"LastItemModifiedDate"`
+	LastItemUserModifiedDate string `json:"LastItemUserModifiedDate"`
+	EnableVersioning         bool   `json:"EnableVersioning"`
+	EnableMinorVersions      bool   `json:"EnableMinorVersions"`
+	MajorVersionLimit        int    `json:"MajorVersionLimit"`
+	Hidden                   bool   `json:"Hidden"`
+	HasUniqueRoleAssignments bool   `json:"HasUniqueRoleAssignments"`
+
+	WebURL string `json:"-"`
+}
+
+var inventoryProps = []string{
+	"Id", "Title", "Description", "RootFolder/ServerRelativeUrl", "BaseTemplate", "BaseType", "ItemCount",
+	"Created", "LastItemModifiedDate", "LastItemUserModifiedDate", "EnableVersioning", "EnableMinorVersions",
+	"MajorVersionLimit", "Hidden", "HasUniqueRoleAssignments",
+}
+
+// GetInventoryTable returns lists inventory table
+func (l *Lists) GetInventoryTable(spec InventorySpec) *schema.Table {
+	return &schema.Table{
+		Name:        "sharepoint_lists",
+		Description: "Lists inventory",
+		Columns: []schema.Column{
+			{Name: "id", Type: types.UUID, PrimaryKey: true, Resolver: schema.PathResolver("ID")},
+			{Name: "web_url", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("WebURL")},
+			{Name: "title", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Title")},
+			{Name: "description", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Description")},
+			{Name: "url", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("RootFolder.ServerRelativeURL")},
+			{Name: "base_template", Type: arrow.PrimitiveTypes.Int32, Resolver: schema.PathResolver("BaseTemplate")},
+			{Name: "base_type", Type: arrow.PrimitiveTypes.Int32, Resolver: schema.PathResolver("BaseType")},
+			{Name: "item_count", Type: arrow.PrimitiveTypes.Int32, Resolver: schema.PathResolver("ItemCount")},
+			{Name: "created", Type: arrow.FixedWidthTypes.Timestamp_us, Resolver: schema.PathResolver("Created")},
+			{Name: "last_item_modified", Type: arrow.FixedWidthTypes.Timestamp_us, Resolver: schema.PathResolver("LastItemModifiedDate")},
+			{Name: "last_item_user_modified", Type: arrow.FixedWidthTypes.Timestamp_us, Resolver: schema.PathResolver("LastItemUserModifiedDate")},
+			{Name: "versioning", Type: arrow.FixedWidthTypes.Boolean, Resolver: schema.PathResolver("EnableVersioning")},
+			{Name: "minor_versions", Type: arrow.FixedWidthTypes.Boolean, Resolver: schema.PathResolver("EnableMinorVersions")},
+			{Name: "major_version_limit", Type: arrow.PrimitiveTypes.Int32, Resolver: schema.PathResolver("MajorVersionLimit")},
+			{Name: "hidden", Type: arrow.FixedWidthTypes.Boolean, Resolver: schema.PathResolver("Hidden")},
+			{Name: "has_unique_permissions", Type: arrow.FixedWidthTypes.Boolean, Resolver: schema.PathResolver("HasUniqueRoleAssignments")},
+		},
+		Resolver: l.InventoryResolver(spec),
+	}
+}
+
+func (l *Lists) InventoryResolver(spec InventorySpec) ResolverClosure {
+	return func(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error {
+		webURLs := []string{l.sp.ToURL()}
+		// Subwebs which can't be read are skipped together with their subwebs
+		skipped := map[string]bool{}
+		if spec.Subwebs {
+			var err error
+			webURLs, err = util.GetAccessibleWebs(l.sp, l.sp.ToURL(), func(webURL string, err error) {
+				l.logger.Warn().Err(err).Str("web", webURL).Msg("failed to get subwebs, skipping")
+				skipped[webURL] = true
+			})
+			if err != nil {
+				return fmt.Errorf("failed to get webs: %w", err)
+			}
+		}
+
+		for _, webURL := range webURLs {
+			if skipped[webURL] {
+				continue
+			}
+			if err := l.syncInventory(ctx, spec, webURL, res); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func (l *Lists) syncInventory(ctx context.Context, spec InventorySpec, webURL string, res chan<- any) error {
+	lists := util.GetWeb(l.sp, webURL).Lists().
+		Select(strings.Join(inventoryProps, ",")).
+		Expand("RootFolder").
+		Top(5000)
+	if !spec.Hidden {
+		lists = lists.Filter("Hidden eq false")
+	}
+
+	resp, err := lists.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get lists of %s: %w", webURL, err)
+	}
+
+	var infos []*inventoryInfo
+	if err := json.Unmarshal(resp.Normalized(), &infos); err != nil {
+		return fmt.Errorf("failed to unmarshal lists: %w", err)
+	}
+
+	for _, info := range infos {
+		info.WebURL = webURL
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case res <- info:
+		}
+	}
+
+	return nil
+}
@@ -8,27 +8,44 @@ import (
 
 	"github.com/apache/arrow/go/v14/arrow"
 	"github.com/cloudquery/plugin-sdk/v4/schema"
-	"github.com/cloudquery/plugin-sdk/v4/types"
+	"github.com/koltyakov/cq-source-sharepoint/internal/fields"
+	"github.com/koltyakov/cq-source-sharepoint/internal/lookups"
 	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+	"github.com/koltyakov/cq-source-sharepoint/resources/services/drift"
 	"github.com/koltyakov/gosip/api"
 	"github.com/rs/zerolog"
 	"github.com/thoas/go-funk"
 )
 
 type Lists struct {
-	sp     *api.SP
-	logger zerolog.Logger
+	sp      *api.SP
+	logger  zerolog.Logger
+	targets map[string][]*listTarget
+	drift   *drift.Report
 }
 
-func NewLists(sp *api.SP, logger zerolog.Logger) *Lists {
+// NewLists creates lists tables builder, schema drift detection is disabled when report is nil
+func NewLists(sp *api.SP, logger zerolog.Logger, report *drift.Report) *Lists {
 	return &Lists{
-		sp:     sp,
-		logger: logger,
+		sp:      sp,
+		logger:  logger,
+		targets: map[string][]*listTarget{},
+		drift:   report,
 	}
 }
 
 func (l *Lists) GetDestTable(listURI string, spec Spec) (*schema.Table, error) {
-	listInfo, err := l.getListInfo(listURI)
+	targets, err := l.getTargets(listURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve lists \"%s\": %w", listURI, err)
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no lists found matching \"%s\"", listURI)
+	}
+
+	list := l.getList(targets[0])
+
+	listInfo, err := getListInfo(list)
 	if err != nil {
 		// ToDo: Decide which design is better to warn and go next or fail a sync
 		// Will stay with a fast fail strateg for now so a user will know about an error immediately
@@ -45,30 +62,88 @@ func (l *Lists) GetDestTable(listURI string, spec Spec) (*schema.Table, error) {
 	lURI := util.RemoveRelativeURLPrefix(listInfo.RootFolder.ServerRelativeURL, siteURL)
 
 	tableName := util.NormalizeEntityName(lURI)
+	if isMultiSite(listURI) {
+		tableName = l.getTableNameFromKey(listURI)
+	}
 	if spec.Alias != "" {
 		tableName = util.NormalizeEntityName(spec.Alias)
 	}
 
 	table := &schema.Table{
-		Name:        "sharepoint_" + tableName,
-		Description: listInfo.Description,
+		Name:          "sharepoint_" + tableName,
+		Description:   listInfo.Description,
+		IsIncremental: spec.Incremental,
 	}
 
-	fields, err := l.sp.Web().GetList(listURI).Fields().Get()
+	fieldsResp, err := list.Fields().Get()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get fields: %w", err)
 	}
 
-	fieldsData := fields.Data()
+	fieldsData := fieldsResp.Data()
+
+	if spec.AutoSelect.Enabled {
+		props, err := getAutoSelectProps(spec, fieldsResp.Normalized())
+		if err != nil {
+			return nil, err
+		}
+		l.logger.Debug().Str("table", tableName).Strs("props", props).Msg("auto selected fields")
+		spec.Select = util.ConcatSlice(spec.Select, props)
+	}
+
+	if l.drift != nil {
+		spec.Select, err = l.detectDrift(listURI, table.Name, spec, fieldsResp.Normalized())
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// ToDo: Rearchitect table construction logic
 	for _, prop := range spec.Select {
 		col := l.getDestCol(prop, tableName, spec, fieldsData)
+		col.IncrementalKey = spec.Incremental && prop == "Modified"
 		table.Columns = append(table.Columns, col)
 	}
 
+	table.Columns = append(table.Columns, fileColumns(spec)...)
+
+	// Lists from different sites land in the same table
+	if isMultiSite(listURI) {
+		table.Columns = append(table.Columns, targetColumns()...)
+	}
+
+	if spec.ResolveLookups {
+		spec.lookupFields, err = lookups.GetFields(spec.Select, fieldsResp.Normalized(), spec.fieldsMapping)
+		if err != nil {
+			return nil, err
+		}
+		table.Columns = append(table.Columns, lookups.Columns(spec.lookupFields, table)...)
+	}
+
+	if spec.CAML != "" {
+		spec.camlFields = getCamlFields(spec.Select, fieldsData)
+	}
+
+	// Managed metadata labels are only available from TaxCatchAll lookup in REST API
+	if spec.CAML == "" && hasTaxonomyFields(spec.Select, fieldsData) {
+		spec.extraSelect = append(spec.extraSelect, taxCatchAllProps...)
+		spec.extraExpand = append(spec.extraExpand, "TaxCatchAll")
+	}
+
 	table.Resolver = l.Resolver(listURI, spec, table)
 
+	if spec.Attachments.Enabled {
+		table.Relations = append(table.Relations, l.getAttachmentsTable(listURI, spec, table))
+	}
+
+	if spec.Versions {
+		table.Relations = append(table.Relations, l.getVersionsTable(listURI, spec, table, tableName, fieldsData))
+	}
+
+	if spec.Permissions {
+		table.Relations = append(table.Relations, l.getPermissionsTable(listURI, table))
+	}
+
 	return table, nil
 }
 
@@ -92,8 +167,13 @@ func (l *Lists) getDestCol(prop string, tableName string, spec Spec, fieldsData
 		fieldAlias = a
 	}
 
+	var fieldType *fields.Type
+
 	valueResolver := func(ctx context.Context, meta schema.ClientMeta, resource *schema.Resource, c schema.Column) error {
 		value := util.GetRespValByProp(resource.Item.(map[string]any), prop)
+		if fieldType != nil {
+			value = fieldType.Convert(value)
+		}
 		if c.Type == arrow.BinaryTypes.String {
 			if value != nil {
 				value = fmt.Sprintf("%v", value)
@@ -113,15 +193,20 @@ func (l *Lists) getDestCol(prop string, tableName string, spec Spec, fieldsData
 	}
 
 	field.InternalName = fieldAlias
-	col := l.columnFromField(field, tableName)
+	var col schema.Column
+	col, fieldType = l.columnFromField(field, tableName)
 	col.PrimaryKey = prop == "ID" // ToDo: Decide on ID cunstruction logic: use ID/UniqueID/Path+ID
 	col.Description = prop
 	col.Resolver = valueResolver
+	if isTaxonomyField(field) {
+		col.Resolver = taxonomyResolver(prop)
+	}
 
 	return col
 }
 
 type listInfo struct {
+	ID          string `json:"Id"`
 	Title       string `json:"Title"`
 	Description string `json:"Description"`
 	RootFolder  struct {
@@ -129,10 +214,8 @@ type listInfo struct {
 	} `json:"RootFolder"`
 }
 
-func (l *Lists) getListInfo(listURI string) (*listInfo, error) {
-	list := l.sp.Web().GetList(listURI)
-
-	listResp, err := list.Select("Title,Description,RootFolder/ServerRelativeUrl").Expand("RootFolder").Get()
+func getListInfo(list *api.List) (*listInfo, error) {
+	listResp, err := list.Select("Id,Title,Description,RootFolder/ServerRelativeUrl").Expand("RootFolder").Get()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get list: %w", err)
 	}
@@ -145,53 +228,33 @@ func (l *Lists) getListInfo(listURI string) (*listInfo, error) {
 	return listInfo, nil
 }
 
-func (l *Lists) columnFromField(field *api.FieldInfo, tableName string) schema.Column {
+func (l *Lists) columnFromField(field *api.FieldInfo, tableName string) (schema.Column, *fields.Type) {
 	logger := l.logger.With().Str("table", tableName).Logger()
 
 	c := schema.Column{
 		Description: field.Description,
 	}
 
-	switch field.TypeAsString {
-	case "Text", "Note", "ContentTypeId":
-		c.Type = arrow.BinaryTypes.String
-	case "Integer", "Counter":
-		c.Type = arrow.PrimitiveTypes.Int32
-	case "Currency":
-		c.Type = arrow.PrimitiveTypes.Float32
-	case "Number":
-		c.Type = arrow.PrimitiveTypes.Float32
-	case "DateTime":
-		c.Type = arrow.FixedWidthTypes.Timestamp_us
-	case "Boolean", "Attachments":
-		c.Type = arrow.FixedWidthTypes.Boolean
-	case "Guid":
-		c.Type = types.UUID
-	case "Lookup", "User":
-		c.Type = arrow.PrimitiveTypes.Int32
-	case "LookupMulti", "UserMulti":
-		c.Type = arrow.ListOf(arrow.PrimitiveTypes.Int32)
-	case "Choice":
-		c.Type = arrow.BinaryTypes.String
-	case "MultiChoice":
-		c.Type = arrow.ListOf(arrow.BinaryTypes.String)
-	case "Computed":
-		c.Type = arrow.BinaryTypes.String
-	case "File":
-		c.Type = arrow.BinaryTypes.String
-	default:
-		logger.Warn().Str("type", field.TypeAsString).Int("kind", field.FieldTypeKind).Str("field_title", field.Title).Str("field_id", field.ID).Msg("unknown type, assuming JSON")
-		c.Type = arrow.BinaryTypes.String
+	fieldType, ok := fields.TypeOf(field)
+	if !ok {
+		logger.Warn().Str("type", field.TypeAsString).Int("kind", field.FieldTypeKind).Str("field_title", field.Title).Str("field_id", field.ID).Msg("unknown type, assuming string")
 	}
 
+	c.Type = fieldType.Arrow
 	c.Name = util.NormalizeEntityName(field.InternalName)
 
-	return c
+	return c, fieldType
 }
 
 func typeFromPropName(prop string) arrow.DataType {
+	if t, ok := fileProps[prop]; ok {
+		return t
+	}
 	if strings.HasSuffix(prop, "/Id") && prop != "ParentList/Id" {
 		return arrow.PrimitiveTypes.Int32
 	}
+	if prop == "HasUniqueRoleAssignments" {
+		return arrow.FixedWidthTypes.Boolean
+	}
 	return arrow.BinaryTypes.String
 }
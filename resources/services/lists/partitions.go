@@ -0,0 +1,62 @@
+package lists
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/koltyakov/cq-source-sharepoint/internal/lookups"
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+	"github.com/koltyakov/cq-source-sharepoint/internal/watermark"
+	"golang.org/x/sync/errgroup"
+)
+
+// syncPartitions splits list reads into ID ranges so each query stays under the list view threshold
+// Partitions are fetched concurrently with the configured degree of parallelism
+func (l *Lists) syncPartitions(ctx context.Context, t *listTarget, spec Spec, filter string, wm *watermark.Watermark, lr *lookups.Resolver, res chan<- any) error {
+	maxID, err := l.getMaxItemID(t)
+	if err != nil {
+		return err
+	}
+
+	l.logger.Debug().Str("list", t.ListURI).Int("max_id", maxID).Int("partition_size", spec.PartitionSize).Msg("partitioned sync")
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(spec.Concurrency)
+
+	for _, partFilter := range partitionFilters(maxID, spec.PartitionSize, filter) {
+		partFilter := partFilter
+		g.Go(func() error {
+			return l.syncItems(gctx, t, spec, partFilter, wm, lr, res)
+		})
+	}
+
+	return g.Wait()
+}
+
+// partitionFilters returns ID range filters covering items from 1 to maxID combined with a filter
+func partitionFilters(maxID int, size int, filter string) []string {
+	var filters []string
+	for start := 1; start <= maxID; start += size {
+		filters = append(filters, util.AndFilters(fmt.Sprintf("ID ge %d and ID lt %d", start, start+size), filter))
+	}
+	return filters
+}
+
+// getMaxItemID returns the largest item ID in a list
+func (l *Lists) getMaxItemID(t *listTarget) (int, error) {
+	resp, err := l.getList(t).Items().
+		Select("ID").
+		OrderBy("ID", false).
+		Top(1).
+		Get()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get max item ID: %w", err)
+	}
+
+	items := resp.Data()
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	return items[0].Data().ID, nil
+}
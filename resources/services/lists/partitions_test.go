@@ -0,0 +1,31 @@
+package lists
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPartitionFilters(t *testing.T) {
+	cases := []struct {
+		name   string
+		maxID  int
+		size   int
+		filter string
+		want   []string
+	}{
+		{"empty list", 0, 1000, "", nil},
+		{"single item", 1, 1000, "", []string{"ID ge 1 and ID lt 1001"}},
+		{"max ID on partition edge", 2000, 1000, "", []string{"ID ge 1 and ID lt 1001", "ID ge 1001 and ID lt 2001"}},
+		{"max ID after partition edge", 2001, 1000, "", []string{"ID ge 1 and ID lt 1001", "ID ge 1001 and ID lt 2001", "ID ge 2001 and ID lt 3001"}},
+		{"with filter", 10, 5, "Status eq 'Active'", []string{
+			"(ID ge 1 and ID lt 6) and (Status eq 'Active')",
+			"(ID ge 6 and ID lt 11) and (Status eq 'Active')",
+		}},
+	}
+
+	for _, c := range cases {
+		if got := partitionFilters(c.maxID, c.size, c.filter); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: partitionFilters(%d, %d, %q) = %q, want %q", c.name, c.maxID, c.size, c.filter, got, c.want)
+		}
+	}
+}
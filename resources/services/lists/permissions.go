@@ -0,0 +1,101 @@
+package lists
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+)
+
+type roleAssignment struct {
+	PrincipalID int `json:"PrincipalId"`
+	Member      struct {
+		LoginName     string `json:"LoginName"`
+		Title         string `json:"Title"`
+		PrincipalType int    `json:"PrincipalType"`
+	} `json:"Member"`
+	RoleDefinitionBindings []struct {
+		Name string `json:"Name"`
+	} `json:"RoleDefinitionBindings"`
+}
+
+// getPermissionsTable returns a child table with role assignments of items with unique permissions
+func (l *Lists) getPermissionsTable(listURI string, listTable *schema.Table) *schema.Table {
+	table := &schema.Table{
+		Name:        listTable.Name + "_permissions",
+		Description: "Role assignments of " + listURI + " items with unique permissions",
+		Columns: []schema.Column{
+			{Name: "item_id", Type: arrow.PrimitiveTypes.Int32, Description: "ItemId", PrimaryKey: true, Resolver: propResolver("ItemId")},
+			{Name: "principal_id", Type: arrow.PrimitiveTypes.Int32, Description: "PrincipalId", PrimaryKey: true, Resolver: propResolver("PrincipalId")},
+			{Name: "principal_type", Type: arrow.PrimitiveTypes.Int32, Description: "Member/PrincipalType", Resolver: propResolver("PrincipalType")},
+			{Name: "login_name", Type: arrow.BinaryTypes.String, Description: "Member/LoginName", Resolver: propResolver("LoginName")},
+			{Name: "title", Type: arrow.BinaryTypes.String, Description: "Member/Title", Resolver: propResolver("Title")},
+			{Name: "role_names", Type: arrow.ListOf(arrow.BinaryTypes.String), Description: "RoleDefinitionBindings/Name", Resolver: propResolver("RoleNames")},
+		},
+	}
+
+	if isMultiSite(listURI) {
+		table.Columns = append(table.Columns, targetColumns()...)
+	}
+
+	table.Resolver = l.PermissionsResolver(listURI)
+
+	return table
+}
+
+func (l *Lists) PermissionsResolver(listURI string) ResolverClosure {
+	targets := l.targets[listURI]
+
+	return func(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error {
+		item := parent.Item.(map[string]any)
+		if unique, _ := item["HasUniqueRoleAssignments"].(bool); !unique {
+			return nil
+		}
+
+		t := getItemTarget(targets, item)
+		itemID := getItemID(item)
+
+		resp, err := l.getList(t).Items().GetByID(itemID).
+			Select("RoleAssignments/PrincipalId,RoleAssignments/Member/LoginName,RoleAssignments/Member/Title,RoleAssignments/Member/PrincipalType,RoleAssignments/RoleDefinitionBindings/Name").
+			Expand("RoleAssignments,RoleAssignments/Member,RoleAssignments/RoleDefinitionBindings").
+			Get()
+		if err != nil {
+			return fmt.Errorf("failed to get role assignments of item %d: %w", itemID, err)
+		}
+
+		var itemRoles struct {
+			RoleAssignments []*roleAssignment `json:"RoleAssignments"`
+		}
+		if err := json.Unmarshal(resp.Normalized(), &itemRoles); err != nil {
+			return fmt.Errorf("failed to unmarshal role assignments: %w", err)
+		}
+
+		rows := make([]map[string]any, len(itemRoles.RoleAssignments))
+		for i, ra := range itemRoles.RoleAssignments {
+			roleNames := make([]string, len(ra.RoleDefinitionBindings))
+			for j, rd := range ra.RoleDefinitionBindings {
+				roleNames[j] = rd.Name
+			}
+			rows[i] = map[string]any{
+				"ItemId":        itemID,
+				"PrincipalId":   ra.PrincipalID,
+				"PrincipalType": ra.Member.PrincipalType,
+				"LoginName":     ra.Member.LoginName,
+				"Title":         ra.Member.Title,
+				"RoleNames":     roleNames,
+			}
+		}
+
+		t.setTargetProps(rows)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case res <- rows:
+		}
+
+		return nil
+	}
+}
@@ -7,50 +7,122 @@ import (
 	"strings"
 
 	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/koltyakov/cq-source-sharepoint/internal/lookups"
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+	"github.com/koltyakov/cq-source-sharepoint/internal/watermark"
 )
 
 type ResolverClosure = func(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error
 
 func (l *Lists) Resolver(listURI string, spec Spec, table *schema.Table) ResolverClosure {
+	targets := l.targets[listURI]
+
 	return func(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error {
 		logger := l.logger.With().Str("table", table.Name).Logger()
 
 		logger.Debug().Strs("cols", spec.Select).Msg("selecting columns from list")
 
-		top := 5000
-		if spec.Top > 0 && spec.Top < 5000 {
-			top = spec.Top
-		}
-
-		list := l.sp.Web().GetList(listURI)
-		items, err := list.Items().
-			Select(strings.Join(spec.Select, ",")).
-			Expand(strings.Join(spec.Expand, ",")).
-			Filter(spec.Filter).
-			Top(top).GetPaged()
+		// Lookups cache is shared by all lists of the table during a sync
+		lr := lookups.NewResolver(l.sp, logger, spec.lookupFields)
 
-		for {
-			if err != nil {
-				return fmt.Errorf("failed to get items: %w", err)
+		for _, t := range targets {
+			if t.WebURL != "" {
+				logger.Debug().Str("web", t.WebURL).Str("list", t.ListURI).Msg("list sync")
 			}
-
-			var itemList []map[string]any
-			if err := json.Unmarshal(items.Items.Normalized(), &itemList); err != nil {
+			if err := l.syncList(ctx, meta, t, spec, table, lr, res); err != nil {
 				return err
 			}
+		}
 
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case res <- itemList:
-			}
+		return nil
+	}
+}
 
-			if !items.HasNextPage() {
-				break
-			}
-			items, err = items.GetNextPage()
+func (l *Lists) syncList(ctx context.Context, meta schema.ClientMeta, t *listTarget, spec Spec, table *schema.Table, lr *lookups.Resolver, res chan<- any) error {
+	filter := spec.Filter
+
+	var wm *watermark.Watermark
+	if spec.Incremental {
+		var err error
+		wm, err = watermark.Get(ctx, util.GetState(meta), t.stateKey(table.Name))
+		if err != nil {
+			return err
 		}
+		filter = util.AndFilters(filter, wm.Filter(*spec.IncrementalOverlap))
+		l.logger.Debug().Str("table", table.Name).Str("filter", filter).Msg("incremental sync")
+	}
 
-		return nil
+	// Partitioned fetching and CAML queries are alternatives to a single paged query
+	switch {
+	case spec.CAML != "":
+		if err := l.syncCAML(ctx, t, spec, lr, res); err != nil {
+			return err
+		}
+	case spec.PartitionSize > 0:
+		if err := l.syncPartitions(ctx, t, spec, filter, wm, lr, res); err != nil {
+			return err
+		}
+	default:
+		if err := l.syncItems(ctx, t, spec, filter, wm, lr, res); err != nil {
+			return err
+		}
+	}
+
+	if wm != nil {
+		return wm.Save(ctx, util.GetState(meta))
+	}
+
+	return nil
+}
+
+// syncItems pages through list items matching a filter
+func (l *Lists) syncItems(ctx context.Context, t *listTarget, spec Spec, filter string, wm *watermark.Watermark, lr *lookups.Resolver, res chan<- any) error {
+	top := 5000
+	if spec.Top > 0 && spec.Top < 5000 {
+		top = spec.Top
+	}
+
+	items, err := l.getList(t).Items().
+		Select(strings.Join(util.ConcatSlice(spec.Select, spec.extraSelect), ",")).
+		Expand(strings.Join(util.ConcatSlice(spec.Expand, spec.extraExpand), ",")).
+		Filter(filter).
+		Top(top).GetPaged()
+
+	for {
+		if err != nil {
+			return fmt.Errorf("failed to get items: %w", err)
+		}
+
+		var itemList []map[string]any
+		if err := json.Unmarshal(items.Items.Normalized(), &itemList); err != nil {
+			return err
+		}
+
+		if err := l.fetchFiles(t, itemList, spec); err != nil {
+			return err
+		}
+
+		if err := lr.Resolve(t.WebURL, t.ListID, itemList); err != nil {
+			return err
+		}
+
+		t.setTargetProps(itemList)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case res <- itemList:
+		}
+
+		if wm != nil {
+			wm.Track(itemList)
+		}
+
+		if !items.HasNextPage() {
+			break
+		}
+		items, err = items.GetNextPage()
 	}
+
+	return nil
 }
@@ -2,8 +2,10 @@ package lists
 
 import (
 	"fmt"
+	"path"
 	"strings"
 
+	"github.com/koltyakov/cq-source-sharepoint/internal/lookups"
 	"github.com/koltyakov/cq-source-sharepoint/internal/util"
 	"github.com/thoas/go-funk"
 )
@@ -14,6 +16,9 @@ type Spec struct {
 	// Wildcard selectors `*` are intentionally not supported
 	// If not provided, only default fields will be fetched (ID, Created, AuthorId, Modified, EditorId)
 	Select []string `json:"select"`
+	// Optional, discovers selected fields from list fields metadata
+	// Discovered fields are added to explicitly selected ones
+	AutoSelect AutoSelectSpec `json:"auto_select"`
 	// REST `$expand` OData modificator, fields entity properties array
 	// When expanding an entity use selection of a nested entity property(s)
 	// Optional, and in most of the cases we recommend to avoid it and
@@ -28,12 +33,112 @@ type Spec struct {
 	// In most of the cases you don't need to change this value
 	// It also can't be larger than 5000 anyways
 	Top int `json:"top"`
+	// Optional, splits list reads into ID ranges (`ID ge X and ID lt Y`) of the given size
+	// Keeps filtered and incremental queries under the list view threshold for large lists
+	// Can't be larger than 5000
+	PartitionSize int `json:"partition_size"`
+	// Optional, a number of ID range partitions fetched concurrently
+	// If not provided, 1 will be used
+	Concurrency int `json:"concurrency"`
+	// Optional, CAML query or a complete view XML to fetch items with `RenderListDataAsStream`
+	// Allows getting data only reachable via CAML, e.g. folder scoped or recursive queries
+	// When only a query is provided (`<Where>...</Where>`) it's wrapped into a recursive view with selected fields
+	// Can't be combined with `filter`, `expand`, `files`, `partition_size`, `incremental` and `permissions` options
+	CAML string `json:"caml"`
+	// Optional, a folder to scope CAML query to, server relative or relative to the list root folder
+	Folder string `json:"folder"`
 	// Optional, an alias for the table name
 	// Don't map different lists to the same table - such scenario is not supported
 	Alias string `json:"alias"`
+	// Optional, enables incremental sync based on items `Modified` watermark
+	// The last seen `Modified` value is persisted in CloudQuery state backend
+	// Without a state backend configured in the source, a full sync is performed every run
+	Incremental bool `json:"incremental"`
+	// Optional, an overlap window in minutes subtracted from the watermark to tolerate clock skew
+	// If not provided, 5 minutes will be used, 0 disables the overlap
+	IncrementalOverlap *int `json:"incremental_overlap"`
+	// Optional, enables deleted items detection based on the list change log
+	// Deletions are written to a companion `sharepoint_<list>_deletions` table
+	// The change token is persisted in CloudQuery state backend
+	Deletions bool `json:"deletions"`
+	// Optional, list items attachments child table configuration
+	Attachments AttachmentsSpec `json:"attachments"`
+	// Optional, enables items version history child table `sharepoint_<list>_versions`
+	// Versions are requested per item, so it's expensive for large lists
+	Versions bool `json:"versions"`
+	// Optional, enables items role assignments child table `sharepoint_<list>_permissions`
+	// Role assignments are requested only for items with unique permissions
+	Permissions bool `json:"permissions"`
+	// Optional, document library files metadata and content configuration
+	Files FilesSpec `json:"files"`
+	// Optional, resolves selected lookup and user fields IDs to display values
+	// User fields get `<field>_email`, `<field>_login` and `<field>_title` companion columns,
+	// lookup fields get `<field>_value` column with the lookup field value
+	ResolveLookups bool `json:"resolve_lookups"`
 
 	// Custom fields mapping settings
 	fieldsMapping map[string]string
+	// Selected props to list fields mapping for CAML queries
+	camlFields map[string]*camlField
+	// Props and entities requested in addition to the selected columns
+	extraSelect []string
+	extraExpand []string
+	// Selected lookup and user fields resolved to display values
+	lookupFields []*lookups.Field
+}
+
+// InventorySpec is the configuration for lists inventory table
+type InventorySpec struct {
+	// Whether to sync lists inventory to `sharepoint_lists` table
+	Enabled bool `json:"enabled"`
+	// Optional, whether to include lists of all subwebs recursively
+	Subwebs bool `json:"subwebs"`
+	// Optional, whether to include hidden lists
+	Hidden bool `json:"hidden"`
+}
+
+// AutoSelectSpec is the configuration for list fields discovery
+type AutoSelectSpec struct {
+	// Whether to select list fields automatically
+	Enabled bool `json:"enabled"`
+	// Optional, fields internal or entity property names patterns (e.g. `Project*`) to select
+	// If not provided, all fields are selected
+	Include []string `json:"include"`
+	// Optional, fields internal or entity property names patterns to skip
+	Exclude []string `json:"exclude"`
+	// Optional, whether to select hidden fields
+	Hidden bool `json:"hidden"`
+	// Optional, whether to select read only fields
+	ReadOnly bool `json:"readonly"`
+	// Optional, whether to select system fields inherited from the base type (except `Title`)
+	System bool `json:"system"`
+}
+
+// AttachmentsSpec is the configuration for list items attachments child table
+type AttachmentsSpec struct {
+	// Whether to sync attachments to `sharepoint_<list>_attachments` table
+	Enabled bool `json:"enabled"`
+	// Optional, whether to download attachments content into a binary column
+	Content bool `json:"content"`
+	// Optional, max attachment size in bytes to download content for
+	// If not provided, 10 MiB will be used
+	MaxSize int64 `json:"max_size"`
+}
+
+// FilesSpec is the configuration for document library files
+type FilesSpec struct {
+	// Whether to sync typed file metadata columns (`File/Length`, `File/TimeLastModified`, etc.)
+	Enabled bool `json:"enabled"`
+	// Optional, whether to download files content into a binary `file_content` column
+	Content bool `json:"content"`
+	// Optional, whether to calculate files SHA-256 checksum into `file_sha256` column
+	Hash bool `json:"hash"`
+	// Optional, max file size in bytes to download content or calculate checksum for
+	// If not provided, 50 MiB will be used
+	MaxSize int64 `json:"max_size"`
+	// Optional, file extensions (without a dot) to download content or calculate checksum for
+	// If not provided, all files are processed
+	Extensions []string `json:"extensions"`
 }
 
 // SetDefault sets default values for list spec
@@ -46,6 +151,25 @@ func (s *Spec) SetDefault() {
 	prepProps := []string{"ID"}
 	apndProps := []string{"Created", "AuthorId", "Modified", "EditorId"}
 
+	// Attachments flag is needed to resolve items attachments
+	if s.Attachments.Enabled {
+		apndProps = append(apndProps, "Attachments")
+	}
+
+	// Unique permissions flag is needed to resolve items role assignments
+	if s.Permissions {
+		apndProps = append(apndProps, "HasUniqueRoleAssignments")
+	}
+
+	// File metadata is needed to filter and download files
+	if s.Files.Enabled || s.Files.Content || s.Files.Hash {
+		s.Files.Enabled = true
+		apndProps = append(apndProps, defaultFileProps...)
+		if !funk.ContainsString(s.Expand, "File") {
+			s.Expand = append(s.Expand, "File")
+		}
+	}
+
 	// Extract arrow syntax fields mapping
 	s.fieldsMapping = util.GetFieldsMapping(s.Select)
 	for i, field := range s.Select {
@@ -62,10 +186,64 @@ func (s *Spec) SetDefault() {
 	})
 
 	s.Select = util.ConcatSlice(prepProps, util.ConcatSlice(s.Select, apndProps))
+
+	if s.IncrementalOverlap == nil {
+		overlap := 5
+		s.IncrementalOverlap = &overlap
+	}
+
+	if s.Concurrency == 0 {
+		s.Concurrency = 1
+	}
+
+	if s.Attachments.MaxSize == 0 {
+		s.Attachments.MaxSize = 10 * 1024 * 1024
+	}
+
+	if s.Files.MaxSize == 0 {
+		s.Files.MaxSize = 50 * 1024 * 1024
+	}
+	for i, ext := range s.Files.Extensions {
+		s.Files.Extensions[i] = strings.TrimPrefix(strings.ToLower(ext), ".")
+	}
 }
 
 // Validate validates list spec
 func (s *Spec) Validate() error {
+	if s.IncrementalOverlap != nil && *s.IncrementalOverlap < 0 {
+		return fmt.Errorf("incremental_overlap can't be negative")
+	}
+
+	if s.CAML != "" && (s.Filter != "" || len(s.Expand) > 0 || s.Files.Enabled || s.PartitionSize > 0 || s.Incremental || s.Permissions) {
+		return fmt.Errorf("caml can't be combined with filter, expand, files, partition_size, incremental and permissions options")
+	}
+
+	for _, pattern := range util.ConcatSlice(s.AutoSelect.Include, s.AutoSelect.Exclude) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid auto_select pattern \"%s\": %w", pattern, err)
+		}
+	}
+
+	if s.Folder != "" && s.CAML == "" {
+		return fmt.Errorf("folder can only be used with caml option")
+	}
+
+	if s.PartitionSize < 0 || s.PartitionSize > 5000 {
+		return fmt.Errorf("partition_size should be between 0 and 5000")
+	}
+
+	if s.Concurrency < 0 {
+		return fmt.Errorf("concurrency can't be negative")
+	}
+
+	if s.Attachments.MaxSize < 0 {
+		return fmt.Errorf("attachments max_size can't be negative")
+	}
+
+	if s.Files.MaxSize < 0 {
+		return fmt.Errorf("files max_size can't be negative")
+	}
+
 	aliases := make([]string, len(s.Select))
 	for i, field := range s.Select {
 		aliases[i] = util.NormalizeEntityName(field)
@@ -91,3 +269,22 @@ func (s *Spec) GetAlias(listURI string) string {
 	}
 	return strings.ToLower(s.Alias)
 }
+
+// GetAliases returns aliases for the list table and its companion tables
+func (s *Spec) GetAliases(listURI string) []string {
+	alias := s.GetAlias(listURI)
+	aliases := []string{alias}
+	if s.Deletions {
+		aliases = append(aliases, alias+"_deletions")
+	}
+	if s.Attachments.Enabled {
+		aliases = append(aliases, alias+"_attachments")
+	}
+	if s.Versions {
+		aliases = append(aliases, alias+"_versions")
+	}
+	if s.Permissions {
+		aliases = append(aliases, alias+"_permissions")
+	}
+	return aliases
+}
@@ -0,0 +1,29 @@
+package lists
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetAliases(t *testing.T) {
+	cases := []struct {
+		name string
+		spec Spec
+		want []string
+	}{
+		{"list only", Spec{}, []string{"lists/orders"}},
+		{"alias", Spec{Alias: "Orders"}, []string{"orders"}},
+		{"deletions", Spec{Deletions: true}, []string{"lists/orders", "lists/orders_deletions"}},
+		{
+			name: "all companion tables",
+			spec: Spec{Alias: "orders", Deletions: true, Attachments: AttachmentsSpec{Enabled: true}, Versions: true, Permissions: true},
+			want: []string{"orders", "orders_deletions", "orders_attachments", "orders_versions", "orders_permissions"},
+		},
+	}
+
+	for _, c := range cases {
+		if got := c.spec.GetAliases("Lists/Orders"); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: GetAliases() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
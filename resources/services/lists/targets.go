@@ -0,0 +1,315 @@
+package lists
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/cloudquery/plugin-sdk/v4/types"
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+	"github.com/koltyakov/gosip/api"
+)
+
+// Item props injected into multi-site list items
+const (
+	siteURLProp = "@SiteUrl"
+	webURLProp  = "@WebUrl"
+	listIDProp  = "@ListId"
+)
+
+// listTarget is a list resolved from a list configuration key
+// WebURL and ListID are empty for the lists relative to the context site
+type listTarget struct {
+	SiteURL string
+	WebURL  string
+	ListID  string
+	ListURI string
+}
+
+// stateKey returns state backend key for the list within a table
+func (t *listTarget) stateKey(tableName string) string {
+	if t.ListID == "" {
+		return tableName
+	}
+	return tableName + "_" + t.ListID
+}
+
+// isMultiSite checks if list key is an absolute, a server relative URL or a wildcard pattern
+func isMultiSite(listURI string) bool {
+	return util.IsAbsoluteURL(listURI) || strings.HasPrefix(listURI, "/") || util.IsPattern(listURI)
+}
+
+// getList returns list object for a target
+func (l *Lists) getList(t *listTarget) *api.List {
+	if t.WebURL == "" {
+		return l.sp.Web().GetList(t.ListURI)
+	}
+	return util.GetWeb(l.sp, t.WebURL).Lists().GetByID(t.ListID)
+}
+
+// getTargets resolves lists targeted by a list configuration key
+// Results are cached for the tables built by the same Lists instance
+func (l *Lists) getTargets(listURI string) ([]*listTarget, error) {
+	if targets, ok := l.targets[listURI]; ok {
+		return targets, nil
+	}
+
+	var targets []*listTarget
+	switch {
+	case !isMultiSite(listURI):
+		targets = []*listTarget{{ListURI: listURI}}
+	case util.IsPattern(listURI):
+		tt, err := l.findTargets(listURI)
+		if err != nil {
+			return nil, err
+		}
+		targets = tt
+	default:
+		t, err := l.getTarget(l.toAbsoluteURL(listURI))
+		if err != nil {
+			return nil, err
+		}
+		targets = []*listTarget{t}
+	}
+
+	l.targets[listURI] = targets
+
+	return targets, nil
+}
+
+// getTarget resolves a list by its absolute URL
+func (l *Lists) getTarget(listURL string) (*listTarget, error) {
+	webURL := getWebURLFromListURL(listURL)
+
+	info, err := getListInfo(util.GetWeb(l.sp, webURL).GetList(util.GetRelativeURL(listURL)))
+	if err != nil {
+		return nil, err
+	}
+
+	siteURL, err := l.getSiteURL(webURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &listTarget{
+		SiteURL: siteURL,
+		WebURL:  webURL,
+		ListID:  info.ID,
+		ListURI: info.RootFolder.ServerRelativeURL,
+	}, nil
+}
+
+// findTargets discovers lists matching a wildcard pattern
+// Context site and its subwebs are scanned, site collections are discovered with Search API
+// when the pattern points outside of the context site
+func (l *Lists) findTargets(listURI string) ([]*listTarget, error) {
+	pattern := strings.ToLower(l.toServerRelativeURL(listURI))
+	prefix := util.StaticPrefix(pattern)
+
+	contextURL := strings.TrimSuffix(l.sp.ToURL(), "/")
+	contextRelURL := strings.ToLower(strings.TrimSuffix(util.GetRelativeURL(contextURL), "/"))
+
+	// Web URL to site collection URL mapping of enumeration roots
+	roots := map[string]string{}
+
+	if strings.HasPrefix(prefix, contextRelURL+"/") {
+		siteURL, err := l.getSiteURL(contextURL)
+		if err != nil {
+			return nil, err
+		}
+		roots[contextURL] = siteURL
+	}
+
+	if !strings.HasPrefix(prefix, contextRelURL+"/") || contextRelURL == "" {
+		siteURLs, err := util.GetSiteCollections(l.sp, getOrigin(contextURL)+prefix)
+		if err != nil {
+			l.logger.Warn().Err(err).Str("list", listURI).Msg("failed to discover site collections, only context site is scanned")
+		}
+		for _, siteURL := range siteURLs {
+			// Site collections which can't contain matching lists are not enumerated
+			if !matchWebPrefix(pattern, strings.ToLower(util.GetRelativeURL(siteURL))) {
+				continue
+			}
+			roots[siteURL] = siteURL
+		}
+	}
+
+	rootURLs := make([]string, 0, len(roots))
+	for rootURL := range roots {
+		rootURLs = append(rootURLs, rootURL)
+	}
+	sort.Strings(rootURLs)
+
+	targets := []*listTarget{}
+	seen := map[string]bool{}
+	for _, rootURL := range rootURLs {
+		webURLs, err := util.GetWebs(l.sp, rootURL)
+		if err != nil {
+			l.logger.Warn().Err(err).Str("list", listURI).Str("site", rootURL).Msg("failed to get webs, skipping site")
+			continue
+		}
+
+		for _, webURL := range webURLs {
+			if !matchWebPrefix(pattern, strings.ToLower(util.GetRelativeURL(webURL))) {
+				continue
+			}
+
+			lists, err := l.getWebLists(webURL)
+			if err != nil {
+				l.logger.Warn().Err(err).Str("list", listURI).Str("web", webURL).Msg("failed to get lists, skipping web")
+				continue
+			}
+
+			for _, info := range lists {
+				if ok, _ := path.Match(pattern, strings.ToLower(info.RootFolder.ServerRelativeURL)); !ok || seen[info.ID] {
+					continue
+				}
+				seen[info.ID] = true
+				targets = append(targets, &listTarget{
+					SiteURL: roots[rootURL],
+					WebURL:  webURL,
+					ListID:  info.ID,
+					ListURI: info.RootFolder.ServerRelativeURL,
+				})
+			}
+		}
+	}
+
+	l.logger.Debug().Str("list", listURI).Int("lists", len(targets)).Msg("lists matched by pattern")
+
+	return targets, nil
+}
+
+func (l *Lists) getWebLists(webURL string) ([]*listInfo, error) {
+	resp, err := util.GetWeb(l.sp, webURL).Lists().
+		Select("Id,Title,Description,RootFolder/ServerRelativeUrl").
+		Expand("RootFolder").
+		Filter("Hidden eq false").
+		Top(5000).
+		Get()
+	if err != nil {
+		return nil, err
+	}
+
+	var lists []*listInfo
+	if err := json.Unmarshal(resp.Normalized(), &lists); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal lists: %w", err)
+	}
+
+	return lists, nil
+}
+
+func (l *Lists) getSiteURL(webURL string) (string, error) {
+	resp, err := l.sp.Site().FromURL(webURL + "/_api/Site").Select("Url").Get()
+	if err != nil {
+		return "", fmt.Errorf("failed to get site collection of \"%s\": %w", webURL, err)
+	}
+	return resp.Data().URL, nil
+}
+
+// toAbsoluteURL converts list key to an absolute URL
+func (l *Lists) toAbsoluteURL(listURI string) string {
+	if util.IsAbsoluteURL(listURI) {
+		return listURI
+	}
+	return getOrigin(l.sp.ToURL()) + l.toServerRelativeURL(listURI)
+}
+
+// toServerRelativeURL converts list key to a server relative URL
+// Relative keys are treated as relative to the context site
+func (l *Lists) toServerRelativeURL(listURI string) string {
+	if util.IsAbsoluteURL(listURI) {
+		return strings.TrimPrefix(listURI, getOrigin(listURI))
+	}
+	if strings.HasPrefix(listURI, "/") {
+		return listURI
+	}
+	return strings.TrimSuffix(util.GetRelativeURL(l.sp.ToURL()), "/") + "/" + listURI
+}
+
+// getOrigin returns scheme and host part of an absolute URL
+func getOrigin(absURL string) string {
+	i := strings.Index(absURL, "://")
+	if i == -1 {
+		return ""
+	}
+	if j := strings.Index(absURL[i+3:], "/"); j != -1 {
+		return absURL[:i+3+j]
+	}
+	return absURL
+}
+
+// getWebURLFromListURL trims list root folder from a list absolute URL
+// Lists are expected in `Lists/` or `_catalogs/` folders, libraries are in web's root
+func getWebURLFromListURL(listURL string) string {
+	parts := strings.Split(strings.TrimSuffix(listURL, "/"), "/")
+	n := len(parts) - 1
+	if n > 3 && containsFold([]string{"lists", "_catalogs"}, parts[n-1]) {
+		n--
+	}
+	return strings.Join(parts[:n], "/")
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWebPrefix checks if a web could contain lists matching a pattern
+func matchWebPrefix(pattern string, webRelURL string) bool {
+	patSegs := strings.Split(strings.Trim(pattern, "/"), "/")
+	webSegs := []string{}
+	if w := strings.Trim(webRelURL, "/"); w != "" {
+		webSegs = strings.Split(w, "/")
+	}
+	if len(webSegs) >= len(patSegs) {
+		return false
+	}
+	for i, seg := range webSegs {
+		if ok, _ := path.Match(patSegs[i], seg); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// getTableNameFromKey builds table name from a multi-site list key
+func (l *Lists) getTableNameFromKey(listURI string) string {
+	siteURL := util.GetRelativeURL(l.sp.ToURL())
+	lURI := util.RemoveRelativeURLPrefix(l.toServerRelativeURL(listURI), siteURL)
+	lURI = strings.NewReplacer("*", "", "?", "", "[", "", "]", "").Replace(lURI)
+	tableName := util.NormalizeEntityName(lURI)
+	for strings.Contains(tableName, "__") {
+		tableName = strings.ReplaceAll(tableName, "__", "_")
+	}
+	return tableName
+}
+
+// targetColumns are the columns added to multi-site list tables
+func targetColumns() []schema.Column {
+	return []schema.Column{
+		{Name: "site_url", Type: arrow.BinaryTypes.String, Description: "Site collection URL", Resolver: propResolver(siteURLProp)},
+		{Name: "web_url", Type: arrow.BinaryTypes.String, Description: "Web URL", Resolver: propResolver(webURLProp)},
+		{Name: "list_id", Type: types.UUID, Description: "List ID", PrimaryKey: true, Resolver: propResolver(listIDProp)},
+	}
+}
+
+// setTargetProps injects target info into list items
+func (t *listTarget) setTargetProps(items []map[string]any) {
+	if t.WebURL == "" {
+		return
+	}
+	for _, item := range items {
+		item[siteURLProp] = t.SiteURL
+		item[webURLProp] = t.WebURL
+		item[listIDProp] = t.ListID
+	}
+}
@@ -0,0 +1,48 @@
+package lists
+
+import "testing"
+
+func TestMatchWebPrefix(t *testing.T) {
+	pattern := "/sites/proj-*/lists/projects"
+
+	cases := []struct {
+		webRelURL string
+		want      bool
+	}{
+		{"/", true},
+		{"", true},
+		{"/sites", true},
+		{"/sites/proj-a", true},
+		{"/sites/proj-a/", true},
+		{"/sites/proj-a/sub", false},
+		{"/sites/other", false},
+		{"/teams/proj-a", false},
+		{"/sites/proj-a/sub/deep", false},
+		{"/sites/proj-a/lists/projects", false},
+	}
+
+	for _, c := range cases {
+		if got := matchWebPrefix(pattern, c.webRelURL); got != c.want {
+			t.Errorf("matchWebPrefix(%q, %q) = %v, want %v", pattern, c.webRelURL, got, c.want)
+		}
+	}
+}
+
+func TestGetWebURLFromListURL(t *testing.T) {
+	cases := []struct {
+		listURL string
+		want    string
+	}{
+		{"https://contoso.sharepoint.com/sites/hr/Lists/Projects", "https://contoso.sharepoint.com/sites/hr"},
+		{"https://contoso.sharepoint.com/sites/hr/Lists/Projects/", "https://contoso.sharepoint.com/sites/hr"},
+		{"https://contoso.sharepoint.com/sites/hr/Shared Documents", "https://contoso.sharepoint.com/sites/hr"},
+		{"https://contoso.sharepoint.com/sites/hr/_catalogs/masterpage", "https://contoso.sharepoint.com/sites/hr"},
+		{"https://contoso.sharepoint.com/Lists/Projects", "https://contoso.sharepoint.com"},
+	}
+
+	for _, c := range cases {
+		if got := getWebURLFromListURL(c.listURL); got != c.want {
+			t.Errorf("getWebURLFromListURL(%q) = %q, want %q", c.listURL, got, c.want)
+		}
+	}
+}
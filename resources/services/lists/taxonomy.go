@@ -0,0 +1,93 @@
+package lists
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+	"github.com/koltyakov/gosip/api"
+)
+
+// Hidden lookup with terms labels of all managed metadata fields values of an item
+var taxCatchAllProps = []string{"TaxCatchAll/ID", "TaxCatchAll/Term"}
+
+// isTaxonomyField checks if a field is a managed metadata field
+func isTaxonomyField(field *api.FieldInfo) bool {
+	return field.TypeAsString == "TaxonomyFieldType" || field.TypeAsString == "TaxonomyFieldTypeMulti"
+}
+
+// taxonomyResolver resolves managed metadata values as `{ term_id, label, wss_id }` JSON objects
+// Multi value fields are resolved as arrays of such objects
+func taxonomyResolver(prop string) schema.ColumnResolver {
+	return func(ctx context.Context, meta schema.ClientMeta, resource *schema.Resource, c schema.Column) error {
+		item := resource.Item.(map[string]any)
+		labels := getTaxCatchAllLabels(item)
+
+		switch v := util.GetRespValByProp(item, prop).(type) {
+		case map[string]any:
+			return resource.Set(c.Name, taxonomyValue(v, labels))
+		case []any:
+			values := make([]map[string]any, 0, len(v))
+			for _, term := range v {
+				if t, ok := term.(map[string]any); ok {
+					values = append(values, taxonomyValue(t, labels))
+				}
+			}
+			return resource.Set(c.Name, values)
+		}
+
+		return resource.Set(c.Name, nil)
+	}
+}
+
+// taxonomyValue normalizes a managed metadata value
+// REST API returns `{ Label, TermGuid, WssId }` where Label is the WssId, real labels are taken from TaxCatchAll
+// RenderListDataAsStream returns `{ Label, TermID }` with real labels
+func taxonomyValue(term map[string]any, labels map[string]string) map[string]any {
+	termID := term["TermGuid"]
+	if termID == nil {
+		termID = term["TermID"]
+	}
+
+	value := map[string]any{
+		"term_id": termID,
+		"label":   term["Label"],
+		"wss_id":  term["WssId"],
+	}
+
+	if wssID, ok := term["WssId"]; ok && wssID != nil {
+		if label, ok := labels[fmt.Sprintf("%v", wssID)]; ok {
+			value["label"] = label
+		}
+	}
+
+	return value
+}
+
+// getTaxCatchAllLabels maps WssIds to terms labels from expanded TaxCatchAll lookup
+func getTaxCatchAllLabels(item map[string]any) map[string]string {
+	labels := map[string]string{}
+	catchAll, _ := item["TaxCatchAll"].([]any)
+	for _, t := range catchAll {
+		term, ok := t.(map[string]any)
+		if !ok {
+			continue
+		}
+		if label, ok := term["Term"].(string); ok {
+			labels[fmt.Sprintf("%v", term["ID"])] = label
+		}
+	}
+	return labels
+}
+
+// hasTaxonomyFields checks if any of the selected props is a managed metadata field
+func hasTaxonomyFields(props []string, fieldsData []api.FieldResp) bool {
+	for _, fieldResp := range fieldsData {
+		fieldData := fieldResp.Data()
+		if isTaxonomyField(fieldData) && util.Contains(props, fieldData.EntityPropertyName) {
+			return true
+		}
+	}
+	return false
+}
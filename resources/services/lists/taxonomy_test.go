@@ -0,0 +1,72 @@
+package lists
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetTaxCatchAllLabels(t *testing.T) {
+	cases := []struct {
+		name string
+		item map[string]any
+		want map[string]string
+	}{
+		{"no catch all", map[string]any{}, map[string]string{}},
+		{
+			name: "multiple terms",
+			item: map[string]any{"TaxCatchAll": []any{
+				map[string]any{"ID": float64(3), "Term": "Finance"},
+				map[string]any{"ID": float64(5), "Term": "Legal"},
+				map[string]any{"ID": float64(8)},
+				"invalid",
+			}},
+			want: map[string]string{"3": "Finance", "5": "Legal"},
+		},
+	}
+
+	for _, c := range cases {
+		if got := getTaxCatchAllLabels(c.item); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: getTaxCatchAllLabels() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestTaxonomyValue(t *testing.T) {
+	labels := getTaxCatchAllLabels(map[string]any{"TaxCatchAll": []any{
+		map[string]any{"ID": float64(3), "Term": "Finance"},
+		map[string]any{"ID": float64(5), "Term": "Legal"},
+	}})
+
+	cases := []struct {
+		name string
+		term map[string]any
+		want map[string]any
+	}{
+		{
+			name: "REST value with catch all label",
+			term: map[string]any{"Label": "3", "TermGuid": "a1", "WssId": float64(3)},
+			want: map[string]any{"term_id": "a1", "label": "Finance", "wss_id": float64(3)},
+		},
+		{
+			name: "REST value of a multi value field",
+			term: map[string]any{"Label": "5", "TermGuid": "b2", "WssId": float64(5)},
+			want: map[string]any{"term_id": "b2", "label": "Legal", "wss_id": float64(5)},
+		},
+		{
+			name: "REST value missing in catch all",
+			term: map[string]any{"Label": "9", "TermGuid": "c3", "WssId": float64(9)},
+			want: map[string]any{"term_id": "c3", "label": "9", "wss_id": float64(9)},
+		},
+		{
+			name: "rendered value",
+			term: map[string]any{"Label": "Finance", "TermID": "a1"},
+			want: map[string]any{"term_id": "a1", "label": "Finance", "wss_id": nil},
+		},
+	}
+
+	for _, c := range cases {
+		if got := taxonomyValue(c.term, labels); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: taxonomyValue() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package lists
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+	"github.com/koltyakov/gosip/api"
+)
+
+// Item prop injected with the version's own creation time
+const versionCreatedProp = "@VersionCreated"
+
+// getVersionsTable returns a child table with list items version history
+// Version rows carry the same selected columns as the parent table
+func (l *Lists) getVersionsTable(listURI string, spec Spec, listTable *schema.Table, tableName string, fieldsData []api.FieldResp) *schema.Table {
+	table := &schema.Table{
+		Name:        listTable.Name + "_versions",
+		Description: "Version history of " + listURI + " items",
+		Columns: []schema.Column{
+			{Name: "version_label", Type: arrow.BinaryTypes.String, Description: "VersionLabel", PrimaryKey: true, Resolver: propResolver("VersionLabel")},
+			{Name: "version_id", Type: arrow.PrimitiveTypes.Int32, Description: "VersionId", Resolver: propResolver("VersionId")},
+			{Name: "version_is_current", Type: arrow.FixedWidthTypes.Boolean, Description: "IsCurrentVersion", Resolver: propResolver("IsCurrentVersion")},
+			{Name: "version_editor", Type: arrow.BinaryTypes.String, Description: "Editor/LookupValue", Resolver: propResolver("Editor/LookupValue")},
+			{Name: "version_editor_email", Type: arrow.BinaryTypes.String, Description: "Editor/Email", Resolver: propResolver("Editor/Email")},
+			{Name: "version_created", Type: arrow.FixedWidthTypes.Timestamp_us, Description: "Created", Resolver: propResolver(versionCreatedProp)},
+		},
+	}
+
+	for _, prop := range spec.Select {
+		col := l.getDestCol(prop, tableName, spec, fieldsData)
+		table.Columns = append(table.Columns, col)
+	}
+
+	if isMultiSite(listURI) {
+		table.Columns = append(table.Columns, targetColumns()...)
+	}
+
+	table.Resolver = l.VersionsResolver(listURI, spec, getVersionFields(spec.Select, fieldsData))
+
+	return table
+}
+
+func (l *Lists) VersionsResolver(listURI string, spec Spec, fieldNames map[string]string) ResolverClosure {
+	targets := l.targets[listURI]
+
+	return func(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error {
+		item := parent.Item.(map[string]any)
+		t := getItemTarget(targets, item)
+		itemID := getItemID(item)
+
+		resp, err := l.getList(t).Items().GetByID(itemID).
+			Select("Versions").
+			Expand("Versions").
+			Get()
+		if err != nil {
+			return fmt.Errorf("failed to get versions of item %d: %w", itemID, err)
+		}
+
+		var itemVersions struct {
+			Versions []map[string]any `json:"Versions"`
+		}
+		if err := json.Unmarshal(resp.Normalized(), &itemVersions); err != nil {
+			return fmt.Errorf("failed to unmarshal versions: %w", err)
+		}
+
+		for _, version := range itemVersions.Versions {
+			normalizeVersion(version, item, spec.Select, fieldNames)
+		}
+
+		t.setTargetProps(itemVersions.Versions)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case res <- itemVersions.Versions:
+		}
+
+		return nil
+	}
+}
+
+// getVersionFields maps internal names of selected fields to entity property names
+// Only fields which names differ are mapped (e.g. `_Status` -> `OData__Status`)
+func getVersionFields(props []string, fieldsData []api.FieldResp) map[string]string {
+	names := map[string]string{}
+	for _, fieldResp := range fieldsData {
+		fieldData := fieldResp.Data()
+		if fieldData.InternalName == fieldData.EntityPropertyName {
+			continue
+		}
+		if util.Contains(props, fieldData.EntityPropertyName) || util.Contains(props, fieldData.EntityPropertyName+"Id") {
+			names[fieldData.InternalName] = fieldData.EntityPropertyName
+		}
+	}
+	return names
+}
+
+// normalizeVersion maps version props to the item props shape
+// Versions are keyed by fields internal names while items are keyed by entity property names
+// Versions contain lookup and user fields as `{ LookupId, LookupValue, Email }` objects
+// while items are selected with `FieldNameId` props
+// Version's `Created` is the version creation time which shadows the item's `Created` field
+// Versions have no TaxCatchAll, managed metadata labels are taken from the item's one,
+// terms which are no longer used by the item keep their raw labels
+func normalizeVersion(version map[string]any, item map[string]any, props []string, fieldNames map[string]string) {
+	version["ID"] = item["ID"]
+	version[versionCreatedProp] = version["Created"]
+	version["Created"] = item["Created"]
+
+	for internalName, prop := range fieldNames {
+		if v, ok := version[internalName]; ok {
+			version[prop] = v
+		} else if v, ok := version["OData_"+internalName]; ok {
+			version[prop] = v
+		}
+	}
+
+	if catchAll, ok := item["TaxCatchAll"]; ok {
+		version["TaxCatchAll"] = catchAll
+	}
+
+	for _, prop := range props {
+		if _, ok := version[prop]; ok || !strings.HasSuffix(prop, "Id") {
+			continue
+		}
+
+		switch v := version[strings.TrimSuffix(prop, "Id")].(type) {
+		case map[string]any:
+			version[prop] = v["LookupId"]
+		case []any:
+			ids := make([]any, 0, len(v))
+			for _, lookup := range v {
+				if l, ok := lookup.(map[string]any); ok {
+					ids = append(ids, l["LookupId"])
+				}
+			}
+			version[prop] = ids
+		}
+	}
+}
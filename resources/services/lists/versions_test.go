@@ -0,0 +1,88 @@
+package lists
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeVersion(t *testing.T) {
+	item := map[string]any{
+		"ID":      float64(1),
+		"Created": "2023-01-01T00:00:00Z",
+		"TaxCatchAll": []any{
+			map[string]any{"ID": float64(3), "Term": "Finance"},
+		},
+	}
+
+	cases := []struct {
+		name       string
+		version    map[string]any
+		props      []string
+		fieldNames map[string]string
+		want       map[string]any
+	}{
+		{
+			name:    "created and item ID",
+			version: map[string]any{"Created": "2023-02-01T00:00:00Z", "Title": "v2"},
+			props:   []string{"ID", "Title", "Created"},
+			want: map[string]any{
+				"ID":               float64(1),
+				"Title":            "v2",
+				"Created":          "2023-01-01T00:00:00Z",
+				versionCreatedProp: "2023-02-01T00:00:00Z",
+				"TaxCatchAll":      item["TaxCatchAll"],
+			},
+		},
+		{
+			name: "single and multi lookups",
+			version: map[string]any{
+				"Manager":   map[string]any{"LookupId": float64(7), "LookupValue": "Jane Doe"},
+				"Reviewers": []any{map[string]any{"LookupId": float64(8)}, map[string]any{"LookupId": float64(9)}},
+			},
+			props: []string{"ManagerId", "ReviewersId"},
+			want: map[string]any{
+				"ID":               float64(1),
+				"Created":          "2023-01-01T00:00:00Z",
+				versionCreatedProp: nil,
+				"TaxCatchAll":      item["TaxCatchAll"],
+				"Manager":          map[string]any{"LookupId": float64(7), "LookupValue": "Jane Doe"},
+				"Reviewers":        []any{map[string]any{"LookupId": float64(8)}, map[string]any{"LookupId": float64(9)}},
+				"ManagerId":        float64(7),
+				"ReviewersId":      []any{float64(8), float64(9)},
+			},
+		},
+		{
+			name: "internal names",
+			version: map[string]any{
+				"OData__Status":       "Draft",
+				"Due_x0020_Date":      "2023-03-01T00:00:00Z",
+				"Project_x0020_Owner": map[string]any{"LookupId": float64(5)},
+			},
+			props: []string{"OData__Status", "Due_x005f_x0020_Date", "Project_x005f_x0020_OwnerId"},
+			fieldNames: map[string]string{
+				"_Status":             "OData__Status",
+				"Due_x0020_Date":      "Due_x005f_x0020_Date",
+				"Project_x0020_Owner": "Project_x005f_x0020_Owner",
+			},
+			want: map[string]any{
+				"ID":                          float64(1),
+				"Created":                     "2023-01-01T00:00:00Z",
+				versionCreatedProp:            nil,
+				"TaxCatchAll":                 item["TaxCatchAll"],
+				"OData__Status":               "Draft",
+				"Due_x0020_Date":              "2023-03-01T00:00:00Z",
+				"Due_x005f_x0020_Date":        "2023-03-01T00:00:00Z",
+				"Project_x0020_Owner":         map[string]any{"LookupId": float64(5)},
+				"Project_x005f_x0020_Owner":   map[string]any{"LookupId": float64(5)},
+				"Project_x005f_x0020_OwnerId": float64(5),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		normalizeVersion(c.version, item, c.props, c.fieldNames)
+		if !reflect.DeepEqual(c.version, c.want) {
+			t.Errorf("%s: normalizeVersion() = %v, want %v", c.name, c.version, c.want)
+		}
+	}
+}
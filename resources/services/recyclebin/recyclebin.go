@@ -0,0 +1,63 @@
+package recyclebin
+
+import (
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/cloudquery/plugin-sdk/v4/types"
+	"github.com/koltyakov/gosip/api"
+	"github.com/rs/zerolog"
+)
+
+type RecycleBin struct {
+	sp     *api.SP
+	logger zerolog.Logger
+}
+
+func NewRecycleBin(sp *api.SP, logger zerolog.Logger) *RecycleBin {
+	return &RecycleBin{
+		sp:     sp,
+		logger: logger,
+	}
+}
+
+type recycleBinItem struct {
+	ID             string `json:"Id"`
+	ItemType       int    `json:"ItemType"`
+	ItemState      int    `json:"ItemState"`
+	Title          string `json:"Title"`
+	LeafName       string `json:"LeafName"`
+	DirName        string `json:"DirName"`
+	AuthorName     string `json:"AuthorName"`
+	AuthorEmail    string `json:"AuthorEmail"`
+	DeletedByName  string `json:"DeletedByName"`
+	DeletedByEmail string `json:"DeletedByEmail"`
+	DeletedDate    string `json:"DeletedDate"`
+	Size           any    `json:"Size"` // Edm.Int64 is serialized as a string
+}
+
+var itemProps = []string{
+	"Id", "ItemType", "ItemState", "Title", "LeafName", "DirName", "AuthorName", "AuthorEmail",
+	"DeletedByName", "DeletedByEmail", "DeletedDate", "Size",
+}
+
+func (r *RecycleBin) GetDestTable(spec Spec) (*schema.Table, error) {
+	return &schema.Table{
+		Name:        "sharepoint_recycle_bin",
+		Description: "Recycle bin items",
+		Columns: []schema.Column{
+			{Name: "id", Type: types.UUID, PrimaryKey: true, Resolver: schema.PathResolver("ID")},
+			{Name: "item_type", Type: arrow.PrimitiveTypes.Int32, Description: "1 - File, 3 - ListItem, 4 - List, 5 - Folder, 6 - FolderWithLists, 7 - Attachment, 8 - ListItemVersion, 9 - CascadeParent, 10 - Web", Resolver: schema.PathResolver("ItemType")},
+			{Name: "stage", Type: arrow.PrimitiveTypes.Int32, Description: "1 - first stage, 2 - second stage", Resolver: schema.PathResolver("ItemState")},
+			{Name: "title", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Title")},
+			{Name: "leaf_name", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("LeafName")},
+			{Name: "dir_name", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("DirName")},
+			{Name: "author", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("AuthorName")},
+			{Name: "author_email", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("AuthorEmail")},
+			{Name: "deleted_by", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("DeletedByName")},
+			{Name: "deleted_by_email", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("DeletedByEmail")},
+			{Name: "deleted_date", Type: arrow.FixedWidthTypes.Timestamp_us, Resolver: schema.PathResolver("DeletedDate")},
+			{Name: "size", Type: arrow.PrimitiveTypes.Int64, Resolver: schema.PathResolver("Size")},
+		},
+		Resolver: r.Resolver(spec),
+	}, nil
+}
@@ -0,0 +1,103 @@
+package recyclebin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+	"github.com/koltyakov/gosip/api"
+)
+
+type ResolverClosure = func(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error
+
+// Recycle bin doesn't support `$skip` and `__next` paging,
+// so pages are requested with `Id` keyset: items are ordered by ID and the next page starts after the last seen ID
+const pageSize = 5000
+
+// Second stage recycle bin items state
+const secondStage = 2
+
+func (r *RecycleBin) Resolver(spec Spec) ResolverClosure {
+	return func(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error {
+		// Site collection recycle bin contains both stages
+		if spec.Scope == "site" {
+			return r.syncItems(ctx, r.sp.Site().RecycleBin(), "", nil, res)
+		}
+
+		if err := r.syncItems(ctx, r.sp.Web().RecycleBin(), "", nil, res); err != nil {
+			return err
+		}
+
+		// Web recycle bin only contains first stage items, second stage items are taken
+		// from the site collection recycle bin by the web location
+		webRelURL := strings.ToLower(strings.Trim(util.GetRelativeURL(r.sp.ToURL()), "/"))
+		inWeb := func(item *recycleBinItem) bool {
+			dirName := strings.ToLower(strings.Trim(item.DirName, "/"))
+			return webRelURL == "" || dirName == webRelURL || strings.HasPrefix(dirName, webRelURL+"/")
+		}
+
+		stageFilter := fmt.Sprintf("ItemState eq %d", secondStage)
+		if err := r.syncItems(ctx, r.sp.Site().RecycleBin(), stageFilter, inWeb, res); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			r.logger.Warn().Err(err).Msg("failed to get second stage recycle bin items, site collection admin permissions are required")
+		}
+
+		return nil
+	}
+}
+
+// syncItems pages through recycle bin items matching a filter
+func (r *RecycleBin) syncItems(ctx context.Context, bin *api.RecycleBin, filter string, match func(*recycleBinItem) bool, res chan<- any) error {
+	lastID := ""
+	for {
+		pageFilter := filter
+		if lastID != "" {
+			pageFilter = util.AndFilters(filter, fmt.Sprintf("Id gt guid'%s'", lastID))
+		}
+
+		resp, err := bin.
+			Select(strings.Join(itemProps, ",")).
+			Filter(pageFilter).
+			OrderBy("Id", true).
+			Top(pageSize).
+			Get()
+		if err != nil {
+			return fmt.Errorf("failed to get recycle bin items: %w", err)
+		}
+
+		var items []*recycleBinItem
+		if err := json.Unmarshal(resp.Normalized(), &items); err != nil {
+			return fmt.Errorf("failed to unmarshal recycle bin items: %w", err)
+		}
+
+		matched := make([]*recycleBinItem, 0, len(items))
+		for _, item := range items {
+			if match == nil || match(item) {
+				matched = append(matched, item)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case res <- matched:
+		}
+
+		if len(items) < pageSize {
+			break
+		}
+
+		nextID := items[len(items)-1].ID
+		if strings.EqualFold(nextID, lastID) {
+			return fmt.Errorf("recycle bin paging doesn't progress after item %s", lastID)
+		}
+		lastID = nextID
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package recyclebin
+
+import "fmt"
+
+// Spec is the configuration for recycle bin source
+type Spec struct {
+	// Whether to enable recycle bin sync
+	Enabled bool `json:"enabled"`
+	// Optional, `site` for the site collection recycle bin (requires site collection admin permissions)
+	// or `web` for the context web recycle bin, both stages are synced in both cases
+	// Web second stage items are taken from the site collection recycle bin, skipped without admin permissions
+	// If not provided, `site` will be used
+	Scope string `json:"scope"`
+}
+
+// SetDefault sets default values for recycle bin spec
+func (s *Spec) SetDefault() {
+	if s.Scope == "" {
+		s.Scope = "site"
+	}
+}
+
+// Validate validates recycle bin spec validity
+func (s *Spec) Validate() error {
+	if s.Scope != "site" && s.Scope != "web" {
+		return fmt.Errorf("scope should be either site or web")
+	}
+	return nil
+}
+
+// GetAlias returns the alias for recycle bin table
+func (*Spec) GetAlias() string {
+	return "recycle_bin"
+}
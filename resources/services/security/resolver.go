@@ -0,0 +1,208 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+)
+
+// Site users, groups and group members collections don't support `$skiptoken` paging,
+// so pages are requested by `Id` ranges
+const (
+	usersPageSize  = 5000
+	groupsPageSize = 5000
+)
+
+func (s *Security) UsersResolver(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error {
+	lastID := 0
+	for {
+		resp, err := s.sp.Web().SiteUsers().
+			Select(strings.Join(userProps, ",")).
+			Filter(fmt.Sprintf("Id gt %d", lastID)).
+			OrderBy("Id", true).
+			Top(usersPageSize).
+			Get()
+		if err != nil {
+			return fmt.Errorf("failed to get site users: %w", err)
+		}
+
+		var users []*userInfo
+		if err := json.Unmarshal(resp.Normalized(), &users); err != nil {
+			return fmt.Errorf("failed to unmarshal site users: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case res <- users:
+		}
+
+		if len(users) < usersPageSize {
+			break
+		}
+		lastID = users[len(users)-1].ID
+	}
+
+	return nil
+}
+
+func (s *Security) GroupsResolver(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error {
+	lastID := 0
+	for {
+		resp, err := s.sp.Web().SiteGroups().
+			Select("Id,Title,Description,OwnerTitle").
+			Filter(fmt.Sprintf("Id gt %d", lastID)).
+			OrderBy("Id", true).
+			Top(groupsPageSize).
+			Get()
+		if err != nil {
+			return fmt.Errorf("failed to get site groups: %w", err)
+		}
+
+		var groups []*groupInfo
+		if err := json.Unmarshal(resp.Normalized(), &groups); err != nil {
+			return fmt.Errorf("failed to unmarshal site groups: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case res <- groups:
+		}
+
+		if len(groups) < groupsPageSize {
+			break
+		}
+		lastID = groups[len(groups)-1].ID
+	}
+
+	return nil
+}
+
+// MembersResolver resolves the parent group members
+// Expanded group users are not paged, so members are requested from the group users collection
+func (s *Security) MembersResolver(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error {
+	group := parent.Item.(*groupInfo)
+
+	lastID := 0
+	for {
+		resp, err := s.sp.Web().SiteGroups().GetByID(group.ID).Users().
+			Select(strings.Join(userProps, ",")).
+			Filter(fmt.Sprintf("Id gt %d", lastID)).
+			OrderBy("Id", true).
+			Top(usersPageSize).
+			Get()
+		if err != nil {
+			return fmt.Errorf("failed to get members of group %d: %w", group.ID, err)
+		}
+
+		var users []*userInfo
+		if err := json.Unmarshal(resp.Normalized(), &users); err != nil {
+			return fmt.Errorf("failed to unmarshal group members: %w", err)
+		}
+
+		members := make([]*memberInfo, len(users))
+		for i, u := range users {
+			members[i] = &memberInfo{
+				GroupID:       group.ID,
+				ID:            u.ID,
+				LoginName:     u.LoginName,
+				Email:         u.Email,
+				Title:         u.Title,
+				PrincipalType: u.PrincipalType,
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case res <- members:
+		}
+
+		if len(users) < usersPageSize {
+			break
+		}
+		lastID = users[len(users)-1].ID
+	}
+
+	return nil
+}
+
+func (s *Security) RoleDefinitionsResolver(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error {
+	resp, err := s.sp.Web().
+		Select("RoleDefinitions/Id,RoleDefinitions/Name,RoleDefinitions/Description,RoleDefinitions/Hidden,RoleDefinitions/RoleTypeKind,RoleDefinitions/Order,RoleDefinitions/BasePermissions").
+		Expand("RoleDefinitions").
+		Get()
+	if err != nil {
+		return fmt.Errorf("failed to get role definitions: %w", err)
+	}
+
+	var web struct {
+		RoleDefinitions []*roleDefinitionInfo `json:"RoleDefinitions"`
+	}
+	if err := json.Unmarshal(resp.Normalized(), &web); err != nil {
+		return fmt.Errorf("failed to unmarshal role definitions: %w", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case res <- web.RoleDefinitions:
+	}
+
+	return nil
+}
+
+func (s *Security) RoleAssignmentsResolver(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error {
+	resp, err := s.sp.Web().
+		Select("RoleAssignments/PrincipalId,RoleAssignments/Member/LoginName,RoleAssignments/Member/Title,RoleAssignments/Member/PrincipalType,RoleAssignments/RoleDefinitionBindings/Id,RoleAssignments/RoleDefinitionBindings/Name").
+		Expand("RoleAssignments,RoleAssignments/Member,RoleAssignments/RoleDefinitionBindings").
+		Get()
+	if err != nil {
+		return fmt.Errorf("failed to get role assignments: %w", err)
+	}
+
+	var web struct {
+		RoleAssignments []struct {
+			PrincipalID int `json:"PrincipalId"`
+			Member      struct {
+				LoginName     string `json:"LoginName"`
+				Title         string `json:"Title"`
+				PrincipalType int    `json:"PrincipalType"`
+			} `json:"Member"`
+			RoleDefinitionBindings []struct {
+				ID   int    `json:"Id"`
+				Name string `json:"Name"`
+			} `json:"RoleDefinitionBindings"`
+		} `json:"RoleAssignments"`
+	}
+	if err := json.Unmarshal(resp.Normalized(), &web); err != nil {
+		return fmt.Errorf("failed to unmarshal role assignments: %w", err)
+	}
+
+	// Role assignments are flattened to a row per principal and role definition
+	var assignments []*roleAssignmentInfo
+	for _, ra := range web.RoleAssignments {
+		for _, rd := range ra.RoleDefinitionBindings {
+			assignments = append(assignments, &roleAssignmentInfo{
+				PrincipalID:      ra.PrincipalID,
+				RoleDefinitionID: rd.ID,
+				RoleName:         rd.Name,
+				LoginName:        ra.Member.LoginName,
+				Title:            ra.Member.Title,
+				PrincipalType:    ra.Member.PrincipalType,
+			})
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case res <- assignments:
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package security
+
+import (
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/koltyakov/gosip/api"
+	"github.com/rs/zerolog"
+)
+
+type Security struct {
+	sp     *api.SP
+	logger zerolog.Logger
+}
+
+func NewSecurity(sp *api.SP, logger zerolog.Logger) *Security {
+	return &Security{
+		sp:     sp,
+		logger: logger,
+	}
+}
+
+type userInfo struct {
+	ID                int    `json:"Id"`
+	LoginName         string `json:"LoginName"`
+	Email             string `json:"Email"`
+	Title             string `json:"Title"`
+	IsSiteAdmin       bool   `json:"IsSiteAdmin"`
+	PrincipalType     int    `json:"PrincipalType"`
+	IsHiddenInUI      bool   `json:"IsHiddenInUI"`
+	UserPrincipalName string `json:"UserPrincipalName"`
+}
+
+var userProps = []string{"Id", "LoginName", "Email", "Title", "IsSiteAdmin", "PrincipalType", "IsHiddenInUI", "UserPrincipalName"}
+
+type groupInfo struct {
+	ID          int    `json:"Id"`
+	Title       string `json:"Title"`
+	Description string `json:"Description"`
+	OwnerTitle  string `json:"OwnerTitle"`
+}
+
+type memberInfo struct {
+	GroupID       int
+	ID            int
+	LoginName     string
+	Email         string
+	Title         string
+	PrincipalType int
+}
+
+type roleDefinitionInfo struct {
+	ID              int    `json:"Id"`
+	Name            string `json:"Name"`
+	Description     string `json:"Description"`
+	Hidden          bool   `json:"Hidden"`
+	RoleTypeKind    int    `json:"RoleTypeKind"`
+	Order           int    `json:"Order"`
+	BasePermissions struct {
+		High string `json:"High"`
+		Low  string `json:"Low"`
+	} `json:"BasePermissions"`
+}
+
+type roleAssignmentInfo struct {
+	PrincipalID      int
+	RoleDefinitionID int
+	RoleName         string
+	LoginName        string
+	Title            string
+	PrincipalType    int
+}
+
+func (s *Security) GetUsersTable() *schema.Table {
+	return &schema.Table{
+		Name:        "sharepoint_site_users",
+		Description: "Site users",
+		Columns: []schema.Column{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int32, PrimaryKey: true, Resolver: schema.PathResolver("ID")},
+			{Name: "login_name", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("LoginName")},
+			{Name: "email", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Email")},
+			{Name: "title", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Title")},
+			{Name: "is_site_admin", Type: arrow.FixedWidthTypes.Boolean, Resolver: schema.PathResolver("IsSiteAdmin")},
+			{Name: "principal_type", Type: arrow.PrimitiveTypes.Int32, Resolver: schema.PathResolver("PrincipalType")},
+			{Name: "is_hidden", Type: arrow.FixedWidthTypes.Boolean, Resolver: schema.PathResolver("IsHiddenInUI")},
+			{Name: "upn", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("UserPrincipalName")},
+		},
+		Resolver: s.UsersResolver,
+	}
+}
+
+func (s *Security) GetGroupsTable() *schema.Table {
+	return &schema.Table{
+		Name:        "sharepoint_site_groups",
+		Description: "Site groups",
+		Columns: []schema.Column{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int32, PrimaryKey: true, Resolver: schema.PathResolver("ID")},
+			{Name: "title", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Title")},
+			{Name: "description", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Description")},
+			{Name: "owner", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("OwnerTitle")},
+		},
+		Resolver: s.GroupsResolver,
+		Relations: schema.Tables{
+			{
+				Name:        "sharepoint_site_group_members",
+				Description: "Site groups members",
+				Columns: []schema.Column{
+					{Name: "group_id", Type: arrow.PrimitiveTypes.Int32, PrimaryKey: true, Resolver: schema.PathResolver("GroupID")},
+					{Name: "user_id", Type: arrow.PrimitiveTypes.Int32, PrimaryKey: true, Resolver: schema.PathResolver("ID")},
+					{Name: "login_name", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("LoginName")},
+					{Name: "email", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Email")},
+					{Name: "title", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Title")},
+					{Name: "principal_type", Type: arrow.PrimitiveTypes.Int32, Resolver: schema.PathResolver("PrincipalType")},
+				},
+				Resolver: s.MembersResolver,
+			},
+		},
+	}
+}
+
+func (s *Security) GetRoleDefinitionsTable() *schema.Table {
+	return &schema.Table{
+		Name:        "sharepoint_role_definitions",
+		Description: "Web role definitions",
+		Columns: []schema.Column{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int32, PrimaryKey: true, Resolver: schema.PathResolver("ID")},
+			{Name: "name", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Name")},
+			{Name: "description", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Description")},
+			{Name: "hidden", Type: arrow.FixedWidthTypes.Boolean, Resolver: schema.PathResolver("Hidden")},
+			{Name: "role_type_kind", Type: arrow.PrimitiveTypes.Int32, Resolver: schema.PathResolver("RoleTypeKind")},
+			{Name: "order", Type: arrow.PrimitiveTypes.Int32, Resolver: schema.PathResolver("Order")},
+			{Name: "base_permissions_high", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("BasePermissions.High")},
+			{Name: "base_permissions_low", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("BasePermissions.Low")},
+		},
+		Resolver: s.RoleDefinitionsResolver,
+	}
+}
+
+func (s *Security) GetRoleAssignmentsTable() *schema.Table {
+	return &schema.Table{
+		Name:        "sharepoint_role_assignments",
+		Description: "Web role assignments",
+		Columns: []schema.Column{
+			{Name: "principal_id", Type: arrow.PrimitiveTypes.Int32, PrimaryKey: true, Resolver: schema.PathResolver("PrincipalID")},
+			{Name: "role_definition_id", Type: arrow.PrimitiveTypes.Int32, PrimaryKey: true, Resolver: schema.PathResolver("RoleDefinitionID")},
+			{Name: "role_name", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("RoleName")},
+			{Name: "login_name", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("LoginName")},
+			{Name: "title", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Title")},
+			{Name: "principal_type", Type: arrow.PrimitiveTypes.Int32, Resolver: schema.PathResolver("PrincipalType")},
+		},
+		Resolver: s.RoleAssignmentsResolver,
+	}
+}
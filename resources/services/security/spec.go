@@ -0,0 +1,43 @@
+package security
+
+// Spec is the configuration for site security principals source
+type Spec struct {
+	// Whether to sync site users to `sharepoint_site_users` table
+	Users bool `json:"users"`
+	// Whether to sync site groups to `sharepoint_site_groups` table
+	// and groups membership to `sharepoint_site_group_members` table
+	Groups bool `json:"groups"`
+	// Whether to sync web role definitions to `sharepoint_role_definitions` table
+	RoleDefinitions bool `json:"role_definitions"`
+	// Whether to sync web role assignments to `sharepoint_role_assignments` table
+	RoleAssignments bool `json:"role_assignments"`
+}
+
+// SetDefault sets default values for security spec
+func (*Spec) SetDefault() {
+	// Default values
+}
+
+// Validate validates security spec validity
+func (*Spec) Validate() error {
+	// Nothing to validate
+	return nil
+}
+
+// GetAliases returns the aliases for security tables
+func (s *Spec) GetAliases() []string {
+	var aliases []string
+	if s.Users {
+		aliases = append(aliases, "site_users")
+	}
+	if s.Groups {
+		aliases = append(aliases, "site_groups", "site_group_members")
+	}
+	if s.RoleDefinitions {
+		aliases = append(aliases, "role_definitions")
+	}
+	if s.RoleAssignments {
+		aliases = append(aliases, "role_assignments")
+	}
+	return aliases
+}
@@ -0,0 +1,96 @@
+package webs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+)
+
+type ResolverClosure = func(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error
+
+func (w *Webs) Resolver(spec Spec) ResolverClosure {
+	return func(ctx context.Context, meta schema.ClientMeta, parent *schema.Resource, res chan<- any) error {
+		siteURL := strings.TrimSuffix(w.sp.ToURL(), "/")
+		return w.syncWeb(ctx, spec, siteURL, siteURL, "", 0, res)
+	}
+}
+
+// syncWeb sends a web info and walks its subwebs recursively
+// Subwebs which can't be read are skipped with their branches, the root web errors fail the sync
+func (w *Webs) syncWeb(ctx context.Context, spec Spec, siteURL string, webURL string, parentWebURL string, depth int, res chan<- any) error {
+	relURL := strings.Trim(strings.TrimPrefix(webURL, siteURL), "/")
+	if depth > 0 && matchPatterns(spec.Exclude, relURL) {
+		w.logger.Debug().Str("web", webURL).Msg("web excluded")
+		return nil
+	}
+
+	web := util.GetWeb(w.sp, webURL)
+
+	if len(spec.Include) == 0 || matchPatterns(spec.Include, relURL) {
+		resp, err := web.Select(strings.Join(webProps, ",")).Get()
+		if err != nil {
+			if depth > 0 {
+				w.logger.Warn().Err(err).Str("web", webURL).Msg("failed to get web, skipping")
+				return nil
+			}
+			return fmt.Errorf("failed to get web %s: %w", webURL, err)
+		}
+
+		var info *webInfo
+		if err := json.Unmarshal(resp.Normalized(), &info); err != nil {
+			return fmt.Errorf("failed to unmarshal web: %w", err)
+		}
+		info.Template = fmt.Sprintf("%s#%d", info.WebTemplate, info.Configuration)
+		info.ParentWebURL = parentWebURL
+		info.Depth = depth
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case res <- info:
+		}
+	}
+
+	if spec.Depth > 0 && depth >= spec.Depth {
+		return nil
+	}
+
+	resp, err := web.Webs().Select("Url").Top(5000).Get()
+	if err != nil {
+		if depth > 0 {
+			w.logger.Warn().Err(err).Str("web", webURL).Msg("failed to get subwebs, skipping")
+			return nil
+		}
+		return fmt.Errorf("failed to get subwebs of %s: %w", webURL, err)
+	}
+
+	var subWebs []struct {
+		URL string `json:"Url"`
+	}
+	if err := json.Unmarshal(resp.Normalized(), &subWebs); err != nil {
+		return fmt.Errorf("failed to unmarshal subwebs: %w", err)
+	}
+
+	for _, subWeb := range subWebs {
+		if err := w.syncWeb(ctx, spec, siteURL, subWeb.URL, webURL, depth+1, res); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matchPatterns checks if a relative web URL matches any of patterns, case insensitive
+func matchPatterns(patterns []string, relURL string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(relURL)); ok {
+			return true
+		}
+	}
+	return false
+}
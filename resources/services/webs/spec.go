@@ -0,0 +1,48 @@
+package webs
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/koltyakov/cq-source-sharepoint/internal/util"
+)
+
+// Spec is the configuration for webs inventory source
+type Spec struct {
+	// Whether to enable webs inventory sync
+	Enabled bool `json:"enabled"`
+	// Optional, max depth of subwebs below the context site, e.g. 1 for direct subwebs only
+	// If not provided, all subwebs are synced recursively
+	Depth int `json:"depth"`
+	// Optional, web URLs patterns relative to the context site (e.g. `projects/*`) to sync
+	// Webs which don't match are skipped, but their subwebs are still processed
+	// If not provided, all webs are synced
+	Include []string `json:"include"`
+	// Optional, web URLs patterns relative to the context site to skip with all their subwebs
+	Exclude []string `json:"exclude"`
+}
+
+// SetDefault sets default values for webs spec
+func (*Spec) SetDefault() {
+	// Default values
+}
+
+// Validate validates webs spec validity
+func (s *Spec) Validate() error {
+	if s.Depth < 0 {
+		return fmt.Errorf("depth can't be negative")
+	}
+
+	for _, pattern := range util.ConcatSlice(s.Include, s.Exclude) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern \"%s\": %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// GetAlias returns the alias for webs inventory table
+func (*Spec) GetAlias() string {
+	return "webs"
+}
@@ -0,0 +1,69 @@
+package webs
+
+import (
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/cloudquery/plugin-sdk/v4/schema"
+	"github.com/cloudquery/plugin-sdk/v4/types"
+	"github.com/koltyakov/gosip/api"
+	"github.com/rs/zerolog"
+)
+
+type Webs struct {
+	sp     *api.SP
+	logger zerolog.Logger
+}
+
+func NewWebs(sp *api.SP, logger zerolog.Logger) *Webs {
+	return &Webs{
+		sp:     sp,
+		logger: logger,
+	}
+}
+
+type webInfo struct {
+	ID                       string `json:"Id"`
+	URL                      string `json:"Url"`
+	ServerRelativeURL        string `json:"ServerRelativeUrl"`
+	Title                    string `json:"Title"`
+	Description              string `json:"Description"`
+	WebTemplate              string `json:"WebTemplate"`
+	Configuration            int    `json:"Configuration"`
+	Created                  string `json:"Created"`
+	LastItemModifiedDate     string `json:"LastItemModifiedDate"`
+	LastItemUserModifiedDate string `json:"LastItemUserModifiedDate"`
+	Language                 int    `json:"Language"`
+	HasUniqueRoleAssignments bool   `json:"HasUniqueRoleAssignments"`
+
+	// Traversal info
+	Template     string `json:"-"`
+	ParentWebURL string `json:"-"`
+	Depth        int    `json:"-"`
+}
+
+var webProps = []string{
+	"Id", "Url", "ServerRelativeUrl", "Title", "Description", "WebTemplate", "Configuration",
+	"Created", "LastItemModifiedDate", "LastItemUserModifiedDate", "Language", "HasUniqueRoleAssignments",
+}
+
+func (w *Webs) GetDestTable(spec Spec) (*schema.Table, error) {
+	return &schema.Table{
+		Name:        "sharepoint_webs",
+		Description: "Webs inventory",
+		Columns: []schema.Column{
+			{Name: "id", Type: types.UUID, PrimaryKey: true, Resolver: schema.PathResolver("ID")},
+			{Name: "url", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("URL")},
+			{Name: "server_relative_url", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("ServerRelativeURL")},
+			{Name: "title", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Title")},
+			{Name: "description", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Description")},
+			{Name: "template", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("Template")},
+			{Name: "created", Type: arrow.FixedWidthTypes.Timestamp_us, Resolver: schema.PathResolver("Created")},
+			{Name: "last_item_modified", Type: arrow.FixedWidthTypes.Timestamp_us, Resolver: schema.PathResolver("LastItemModifiedDate")},
+			{Name: "last_item_user_modified", Type: arrow.FixedWidthTypes.Timestamp_us, Resolver: schema.PathResolver("LastItemUserModifiedDate")},
+			{Name: "language", Type: arrow.PrimitiveTypes.Int32, Resolver: schema.PathResolver("Language")},
+			{Name: "parent_web_url", Type: arrow.BinaryTypes.String, Resolver: schema.PathResolver("ParentWebURL")},
+			{Name: "has_unique_permissions", Type: arrow.FixedWidthTypes.Boolean, Resolver: schema.PathResolver("HasUniqueRoleAssignments")},
+			{Name: "depth", Type: arrow.PrimitiveTypes.Int32, Resolver: schema.PathResolver("Depth")},
+		},
+		Resolver: w.Resolver(spec),
+	}, nil
+}